@@ -18,6 +18,9 @@ package controller
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"math/rand"
 	"sync"
 	"time"
 
@@ -72,6 +75,47 @@ var (
 			Help:      "Timestamp of last successful sync with the DNS provider",
 		},
 	)
+	lastSyncAttemptTimestamp = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "last_sync_attempt_timestamp_seconds",
+			Help:      "Timestamp of last sync with the DNS provider, successful or not",
+		},
+	)
+	lastDryRunSyncTimestamp = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "last_dry_run_sync_timestamp_seconds",
+			Help:      "Timestamp of last successful sync with the DNS provider in dry-run mode",
+		},
+	)
+	consecutiveSyncFailures = prometheus.NewGauge(
+		prometheus.GaugeOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "consecutive_sync_failures",
+			Help:      "Number of failed syncs since the last successful sync with the DNS provider",
+		},
+	)
+	cachedRecordsSyncsTotal = prometheus.NewCounter(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "cached_records_syncs_total",
+			Help:      "Number of syncs which reused the DNS records of the previous sync instead of reading them.",
+		},
+	)
+	targetMutationsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "external_dns",
+			Subsystem: "controller",
+			Name:      "target_mutations_total",
+			Help:      "Number of targets created or deleted by the applied changes, counting only the added and removed targets of updated records.",
+		},
+		[]string{"operation"},
+	)
 	controllerNoChangesTotal = prometheus.NewCounter(
 		prometheus.CounterOpts{
 			Namespace: "external_dns",
@@ -126,9 +170,14 @@ func init() {
 	prometheus.MustRegister(sourceEndpointsTotal)
 	prometheus.MustRegister(registryEndpointsTotal)
 	prometheus.MustRegister(lastSyncTimestamp)
+	prometheus.MustRegister(lastSyncAttemptTimestamp)
+	prometheus.MustRegister(lastDryRunSyncTimestamp)
+	prometheus.MustRegister(consecutiveSyncFailures)
 	prometheus.MustRegister(deprecatedRegistryErrors)
 	prometheus.MustRegister(deprecatedSourceErrors)
 	prometheus.MustRegister(controllerNoChangesTotal)
+	prometheus.MustRegister(cachedRecordsSyncsTotal)
+	prometheus.MustRegister(targetMutationsTotal)
 	prometheus.MustRegister(registryARecords)
 	prometheus.MustRegister(sourceARecords)
 	prometheus.MustRegister(verifiedARecords)
@@ -157,24 +206,40 @@ type Controller struct {
 	ManagedRecordTypes []string
 	// MinEventSyncInterval is used as window for batching events
 	MinEventSyncInterval time.Duration
+	// PlanOutput receives a machine-readable copy of every calculated plan before it is applied
+	PlanOutput io.Writer
+	// IntervalJitter randomly varies every scheduled sync by up to this fraction of Interval
+	IntervalJitter float64
+	// MaxRecordsStaleness enables reusing the DNS records of the last sync for up to this duration,
+	// as long as that sync found nothing to change and the sources still yield the same endpoints
+	MaxRecordsStaleness time.Duration
+	// The recordsCache holds the DNS records which can be reused by the next sync
+	recordsCache *recordsCache
+	// DryRun reports successful syncs separately, as no changes are actually made
+	DryRun bool
+	// Debug, if set, receives the desired endpoints and the plan of every sync
+	Debug *DebugState
+	// The stop channel signals that no further changes should be started
+	stop <-chan struct{}
 }
 
 // RunOnce runs a single iteration of a reconciliation loop.
 func (c *Controller) RunOnce(ctx context.Context) error {
-	records, err := c.Registry.Records(ctx)
-	if err != nil {
-		registryErrorsTotal.Inc()
-		deprecatedRegistryErrors.Inc()
+	lastSyncAttemptTimestamp.SetToCurrentTime()
+	if err := c.runOnce(ctx); err != nil {
+		consecutiveSyncFailures.Inc()
 		return err
 	}
+	consecutiveSyncFailures.Set(0)
+	if c.DryRun {
+		lastDryRunSyncTimestamp.SetToCurrentTime()
+	} else {
+		lastSyncTimestamp.SetToCurrentTime()
+	}
+	return nil
+}
 
-	missingRecords := c.Registry.MissingRecords()
-
-	registryEndpointsTotal.Set(float64(len(records)))
-	regARecords := filterARecords(records)
-	registryARecords.Set(float64(len(regARecords)))
-	ctx = context.WithValue(ctx, provider.RecordsContextKey, records)
-
+func (c *Controller) runOnce(ctx context.Context) error {
 	endpoints, err := c.Source.Endpoints(ctx)
 	if err != nil {
 		sourceErrorsTotal.Inc()
@@ -182,8 +247,45 @@ func (c *Controller) RunOnce(ctx context.Context) error {
 		return err
 	}
 	sourceEndpointsTotal.Set(float64(len(endpoints)))
+	if c.Debug != nil {
+		c.Debug.setEndpoints(endpoints)
+	}
 	srcARecords := filterARecords(endpoints)
 	sourceARecords.Set(float64(len(srcARecords)))
+
+	var endpointsHash string
+	if c.MaxRecordsStaleness > 0 {
+		if endpointsHash, err = hashEndpoints(endpoints); err != nil {
+			return err
+		}
+	}
+
+	// The cache is only kept if this sync turns out to have nothing to change.
+	cache := c.recordsCache
+	c.recordsCache = nil
+	var records []*endpoint.Endpoint
+	if c.MaxRecordsStaleness > 0 && cache.valid(endpointsHash, time.Now(), c.MaxRecordsStaleness) {
+		log.Debugf("Desired endpoints are unchanged, reusing DNS records read at %s", cache.readAt.Format(time.RFC3339))
+		records = cache.records
+		cachedRecordsSyncsTotal.Inc()
+	} else {
+		readAt := time.Now()
+		records, err = c.Registry.Records(ctx)
+		if err != nil {
+			registryErrorsTotal.Inc()
+			deprecatedRegistryErrors.Inc()
+			return err
+		}
+		cache = &recordsCache{records: records, endpointsHash: endpointsHash, readAt: readAt}
+	}
+
+	missingRecords := c.Registry.MissingRecords()
+
+	registryEndpointsTotal.Set(float64(len(records)))
+	regARecords := filterARecords(records)
+	registryARecords.Set(float64(len(regARecords)))
+	ctx = context.WithValue(ctx, provider.RecordsContextKey, records)
+
 	vRecords := fetchMatchingARecords(endpoints, records)
 	verifiedARecords.Set(float64(len(vRecords)))
 	endpoints = c.Registry.AdjustEndpoints(endpoints)
@@ -208,6 +310,7 @@ func (c *Controller) RunOnce(ctx context.Context) error {
 				return err
 			}
 			log.Info("All missing records are created")
+			cache = nil
 		}
 	}
 
@@ -222,19 +325,75 @@ func (c *Controller) RunOnce(ctx context.Context) error {
 
 	plan = plan.Calculate()
 
+	if c.Debug != nil {
+		c.Debug.setPlan(plan.Changes)
+	}
+
+	if c.PlanOutput != nil {
+		if err := writePlan(c.PlanOutput, plan.Changes); err != nil {
+			return err
+		}
+	}
+
+	if plan.Changes.HasChanges() && c.stopping() {
+		log.Infof("Shutdown requested, skipping %s", summarizeChanges(plan.Changes))
+		return nil
+	}
+
 	if plan.Changes.HasChanges() {
+		created, deleted := countTargetMutations(plan.Changes)
 		err = c.Registry.ApplyChanges(ctx, plan.Changes)
 		if err != nil {
 			registryErrorsTotal.Inc()
 			deprecatedRegistryErrors.Inc()
-			return err
+			return fmt.Errorf("failed to apply %s: %w", summarizeChanges(plan.Changes), err)
 		}
+		targetMutationsTotal.WithLabelValues("create").Add(float64(created))
+		targetMutationsTotal.WithLabelValues("delete").Add(float64(deleted))
+		log.Infof("Applied %s", summarizeChanges(plan.Changes))
 	} else {
 		controllerNoChangesTotal.Inc()
 		log.Info("All records are already up to date")
+		if c.MaxRecordsStaleness > 0 {
+			c.recordsCache = cache
+		}
+	}
+
+	return nil
+}
+
+// summarizeChanges returns a human readable count of the given changes.
+func summarizeChanges(changes *plan.Changes) string {
+	creates, updates, deletes := len(changes.Create), len(changes.UpdateNew), len(changes.Delete)
+	return fmt.Sprintf("%d changes (%d creates, %d updates, %d deletes)", creates+updates+deletes, creates, updates, deletes)
+}
+
+// countTargetMutations returns the number of targets created and deleted by the changes, as done by
+// a provider storing every target as an individual record. Updates only count their added and
+// removed targets.
+func countTargetMutations(changes *plan.Changes) (created, deleted int) {
+	for _, ep := range changes.Create {
+		created += len(ep.Targets)
+	}
+	for _, ep := range changes.Delete {
+		deleted += len(ep.Targets)
+	}
+	for i := range changes.UpdateNew {
+		if i >= len(changes.UpdateOld) {
+			break
+		}
+		added, removed := plan.TargetChanges(changes.UpdateOld[i], changes.UpdateNew[i])
+		created += len(added)
+		deleted += len(removed)
 	}
+	return created, deleted
+}
 
-	lastSyncTimestamp.SetToCurrentTime()
+// writePlan serializes the changes of a calculated plan to w.
+func writePlan(w io.Writer, changes *plan.Changes) error {
+	if err := plan.WriteChanges(w, changes); err != nil {
+		return fmt.Errorf("failed to write plan: %w", err)
+	}
 	return nil
 }
 
@@ -281,12 +440,41 @@ func (c *Controller) ShouldRunOnce(now time.Time) bool {
 	if now.Before(c.nextRunAt) {
 		return false
 	}
-	c.nextRunAt = now.Add(c.Interval)
+	c.nextRunAt = now.Add(c.jitteredInterval())
 	return true
 }
 
+// jitteredInterval returns Interval randomly shortened or lengthened by up to IntervalJitter,
+// so that instances started at the same time don't keep synchronizing at the same time.
+func (c *Controller) jitteredInterval() time.Duration {
+	if c.IntervalJitter <= 0 {
+		return c.Interval
+	}
+	maxJitter := float64(c.Interval) * c.IntervalJitter
+	return c.Interval + time.Duration((2*rand.Float64()-1)*maxJitter)
+}
+
+// stopping returns true once the stop channel passed to RunUntil is closed.
+func (c *Controller) stopping() bool {
+	select {
+	case <-c.stop:
+		return true
+	default:
+		return false
+	}
+}
+
 // Run runs RunOnce in a loop with a delay until context is canceled
 func (c *Controller) Run(ctx context.Context) {
+	c.RunUntil(ctx, ctx.Done())
+}
+
+// RunUntil runs RunOnce in a loop with a delay until stop is closed. Closing stop lets an
+// in-flight synchronization finish applying its changes, while canceling ctx aborts it.
+// Changes calculated after stop is closed are skipped entirely, so records and their
+// registry records are never split by a shutdown request.
+func (c *Controller) RunUntil(ctx context.Context, stop <-chan struct{}) {
+	c.stop = stop
 	ticker := time.NewTicker(time.Second)
 	defer ticker.Stop()
 	for {
@@ -297,7 +485,7 @@ func (c *Controller) Run(ctx context.Context) {
 		}
 		select {
 		case <-ticker.C:
-		case <-ctx.Done():
+		case <-stop:
 			log.Info("Terminating main controller loop")
 			return
 		}
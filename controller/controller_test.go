@@ -17,14 +17,20 @@ limitations under the License.
 package controller
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"math"
+	"net/http"
+	"net/http/httptest"
 	"reflect"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
 
 	"sigs.k8s.io/external-dns/endpoint"
 	"sigs.k8s.io/external-dns/internal/testutils"
@@ -32,6 +38,7 @@ import (
 	"sigs.k8s.io/external-dns/plan"
 	"sigs.k8s.io/external-dns/provider"
 	"sigs.k8s.io/external-dns/registry"
+	"sigs.k8s.io/external-dns/source"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -327,6 +334,27 @@ func testControllerFiltersDomainsWithMissing(t *testing.T, configuredEndpoints [
 	}
 }
 
+func TestShouldRunOnceWithIntervalJitter(t *testing.T) {
+	ctrl := &Controller{Interval: 10 * time.Minute, IntervalJitter: 0.2, MinEventSyncInterval: 5 * time.Second}
+
+	now := time.Now()
+	assert.True(t, ctrl.ShouldRunOnce(now))
+
+	for i := 0; i < 1000; i++ {
+		interval := ctrl.nextRunAt.Sub(now)
+		assert.GreaterOrEqual(t, interval, 8*time.Minute)
+		assert.LessOrEqual(t, interval, 12*time.Minute)
+
+		now = ctrl.nextRunAt
+		assert.True(t, ctrl.ShouldRunOnce(now))
+	}
+
+	// Events still trigger a run within MinEventSyncInterval
+	ctrl.ScheduleRunOnce(now)
+	assert.False(t, ctrl.ShouldRunOnce(now))
+	assert.True(t, ctrl.ShouldRunOnce(now.Add(5*time.Second)))
+}
+
 func TestControllerSkipsEmptyChanges(t *testing.T) {
 	testControllerFiltersDomains(
 		t,
@@ -628,3 +656,562 @@ func TestMissingRecordsApply(t *testing.T) {
 			},
 		})
 }
+
+// TestRunOnceWritesPlan validates that the calculated plan is written before it is applied.
+func TestRunOnceWritesPlan(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		{
+			DNSName:    "create-record.used.tld",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4"},
+		},
+	}, nil)
+
+	provider := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	var buf bytes.Buffer
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		PlanOutput:         &buf,
+	}
+
+	assert.NoError(t, ctrl.RunOnce(context.Background()))
+	require.Len(t, provider.ApplyChangesCalls, 1)
+
+	var doc plan.ChangesDocument
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &doc))
+	assert.Equal(t, plan.ChangesDocumentVersion, doc.Version)
+	require.Len(t, doc.Create, 1)
+	assert.Equal(t, "create-record.used.tld", doc.Create[0].DNSName)
+	assert.Empty(t, doc.Update)
+	assert.Empty(t, doc.Delete)
+}
+
+func TestRunOnceRecordsDebugState(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		{
+			DNSName:    "create-record.used.tld",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4"},
+		},
+	}, nil)
+
+	provider := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		Debug:              NewDebugState(),
+	}
+	handler := ctrl.Debug.Handler()
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+	assert.Equal(t, http.StatusServiceUnavailable, get("/debug/endpoints").Code)
+	assert.Equal(t, http.StatusServiceUnavailable, get("/debug/plan").Code)
+
+	assert.NoError(t, ctrl.RunOnce(context.Background()))
+	records := provider.RecordsCallCount
+
+	rec := get("/debug/endpoints")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var endpoints []*endpoint.Endpoint
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &endpoints))
+	require.Len(t, endpoints, 1)
+	assert.Equal(t, "create-record.used.tld", endpoints[0].DNSName)
+
+	rec = get("/debug/plan")
+	require.Equal(t, http.StatusOK, rec.Code)
+	var doc plan.ChangesDocument
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	require.Len(t, doc.Create, 1)
+	assert.Equal(t, "create-record.used.tld", doc.Create[0].DNSName)
+
+	// Serving the debug state doesn't read the DNS records.
+	assert.Equal(t, records, provider.RecordsCallCount)
+}
+
+type applyErrorMockProvider struct {
+	filteredMockProvider
+	err error
+}
+
+// ApplyChanges always fails with the configured error
+func (p *applyErrorMockProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	return p.err
+}
+
+// TestRunOnceReportsFailedChanges validates that apply failures are returned with a summary of the plan.
+func TestRunOnceReportsFailedChanges(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		{
+			DNSName:    "create-record.used.tld",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4"},
+		},
+	}, nil)
+
+	applyErr := errors.New("provider rejected changes")
+	provider := &applyErrorMockProvider{
+		filteredMockProvider: filteredMockProvider{
+			RecordsStore: []*endpoint.Endpoint{
+				{
+					DNSName:    "delete-record.used.tld",
+					RecordType: endpoint.RecordTypeA,
+					Targets:    endpoint.Targets{"4.3.2.1"},
+				},
+			},
+		},
+		err: applyErr,
+	}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+	}
+
+	err = ctrl.RunOnce(context.Background())
+	require.Error(t, err)
+	assert.ErrorIs(t, err, applyErr)
+	assert.Contains(t, err.Error(), "2 changes (1 creates, 0 updates, 1 deletes)")
+}
+
+func TestRunOnceTracksConsecutiveSyncFailures(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{}, nil)
+
+	failing, err := registry.NewNoopRegistry(&errorMockProvider{})
+	require.NoError(t, err)
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           failing,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+	}
+
+	lastSyncTimestamp.Set(0)
+	consecutiveSyncFailures.Set(0)
+
+	assert.Error(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, math.Float64bits(1), valueFromMetric(consecutiveSyncFailures))
+	assert.Error(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, math.Float64bits(2), valueFromMetric(consecutiveSyncFailures))
+	assert.NotEqual(t, math.Float64bits(0), valueFromMetric(lastSyncAttemptTimestamp))
+	assert.Equal(t, math.Float64bits(0), valueFromMetric(lastSyncTimestamp))
+
+	succeeding, err := registry.NewNoopRegistry(&filteredMockProvider{})
+	require.NoError(t, err)
+	ctrl.Registry = succeeding
+
+	assert.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, math.Float64bits(0), valueFromMetric(consecutiveSyncFailures))
+	assert.NotEqual(t, math.Float64bits(0), valueFromMetric(lastSyncTimestamp))
+}
+
+func TestRunOnceDryRunSyncTimestamp(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{}, nil)
+
+	r, err := registry.NewNoopRegistry(&filteredMockProvider{})
+	require.NoError(t, err)
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		DryRun:             true,
+	}
+
+	lastSyncTimestamp.Set(0)
+	lastDryRunSyncTimestamp.Set(0)
+
+	assert.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.NotEqual(t, math.Float64bits(0), valueFromMetric(lastDryRunSyncTimestamp))
+	assert.Equal(t, math.Float64bits(0), valueFromMetric(lastSyncTimestamp))
+}
+
+// eventMockSource is a source whose endpoints can be changed while the controller is running.
+type eventMockSource struct {
+	mu        sync.Mutex
+	endpoints []*endpoint.Endpoint
+	handler   func()
+}
+
+func (s *eventMockSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.endpoints, nil
+}
+
+func (s *eventMockSource) AddEventHandler(ctx context.Context, handler func()) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = handler
+}
+
+// update replaces the endpoints of the source and notifies the event handler.
+func (s *eventMockSource) update(endpoints []*endpoint.Endpoint) {
+	s.mu.Lock()
+	s.endpoints = endpoints
+	handler := s.handler
+	s.mu.Unlock()
+	handler()
+}
+
+// syncedMockProvider records the changes passed to ApplyChanges and is safe for concurrent use.
+type syncedMockProvider struct {
+	provider.BaseProvider
+	mu                sync.Mutex
+	applyChangesCalls []*plan.Changes
+}
+
+func (p *syncedMockProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	return nil, nil
+}
+
+func (p *syncedMockProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.applyChangesCalls = append(p.applyChangesCalls, changes)
+	return nil
+}
+
+func (p *syncedMockProvider) calls() []*plan.Changes {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]*plan.Changes{}, p.applyChangesCalls...)
+}
+
+// TestRunAppliesEventTriggeredChanges validates that a source event leads to a sync after
+// MinEventSyncInterval instead of waiting for the full Interval.
+func TestRunAppliesEventTriggeredChanges(t *testing.T) {
+	source := &eventMockSource{}
+	provider := &syncedMockProvider{}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:               source,
+		Registry:             r,
+		Policy:               &plan.SyncPolicy{},
+		ManagedRecordTypes:   []string{endpoint.RecordTypeA},
+		Interval:             time.Hour,
+		MinEventSyncInterval: 100 * time.Millisecond,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ctrl.Source.AddEventHandler(ctx, func() { ctrl.ScheduleRunOnce(time.Now()) })
+	go ctrl.Run(ctx)
+
+	source.update([]*endpoint.Endpoint{
+		{
+			DNSName:    "new-record.used.tld",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4"},
+		},
+	})
+
+	require.Eventually(t, func() bool {
+		return len(provider.calls()) == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	changes := provider.calls()[0]
+	require.Len(t, changes.Create, 1)
+	assert.Equal(t, "new-record.used.tld", changes.Create[0].DNSName)
+}
+
+// TestRunOnceSkipsChangesWhenStopping validates that no changes are started once shutdown is requested.
+func TestRunOnceSkipsChangesWhenStopping(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		{
+			DNSName:    "create-record.used.tld",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4"},
+		},
+	}, nil)
+
+	provider := &filteredMockProvider{}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	stop := make(chan struct{})
+	close(stop)
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		stop:               stop,
+	}
+
+	assert.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Empty(t, provider.ApplyChangesCalls)
+}
+
+// blockingMockProvider blocks in ApplyChanges until it is released.
+type blockingMockProvider struct {
+	provider.BaseProvider
+	started  chan struct{}
+	release  chan struct{}
+	applyErr chan error
+}
+
+func (p *blockingMockProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	return nil, nil
+}
+
+func (p *blockingMockProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	close(p.started)
+	<-p.release
+	p.applyErr <- ctx.Err()
+	return nil
+}
+
+// TestRunUntilFinishesInFlightChanges validates that closing stop lets the in-flight changes finish.
+func TestRunUntilFinishesInFlightChanges(t *testing.T) {
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		{
+			DNSName:    "create-record.used.tld",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4"},
+		},
+	}, nil)
+
+	provider := &blockingMockProvider{
+		started:  make(chan struct{}),
+		release:  make(chan struct{}),
+		applyErr: make(chan error, 1),
+	}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		Interval:           time.Hour,
+	}
+
+	stop := make(chan struct{})
+	done := make(chan struct{})
+	go func() {
+		ctrl.RunUntil(context.Background(), stop)
+		close(done)
+	}()
+
+	<-provider.started
+	close(stop)
+
+	select {
+	case <-done:
+		t.Fatal("controller loop terminated before the in-flight changes were applied")
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	close(provider.release)
+	assert.NoError(t, <-provider.applyErr)
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("controller loop did not terminate after stop was closed")
+	}
+}
+
+func TestRunOnceReusesRecordsWhenSourcesAreUnchanged(t *testing.T) {
+	desired := []*endpoint.Endpoint{
+		{
+			DNSName:    "unchanged-record.used.tld",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4"},
+		},
+	}
+	source := &eventMockSource{endpoints: desired}
+	provider := &filteredMockProvider{
+		RecordsStore: []*endpoint.Endpoint{
+			{
+				DNSName:    "unchanged-record.used.tld",
+				RecordType: endpoint.RecordTypeA,
+				Targets:    endpoint.Targets{"1.2.3.4"},
+			},
+		},
+	}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:              source,
+		Registry:            r,
+		Policy:              &plan.SyncPolicy{},
+		ManagedRecordTypes:  []string{endpoint.RecordTypeA},
+		MaxRecordsStaleness: 10 * time.Minute,
+	}
+
+	before := testutil.ToFloat64(cachedRecordsSyncsTotal)
+
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, 1, provider.RecordsCallCount)
+
+	// Nothing changed, the records of the last sync are reused
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, 1, provider.RecordsCallCount)
+	assert.Equal(t, before+1, testutil.ToFloat64(cachedRecordsSyncsTotal))
+
+	// The cached records are too old
+	ctrl.recordsCache.readAt = time.Now().Add(-time.Hour)
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, 2, provider.RecordsCallCount)
+
+	// The sources changed, the records are read and the change is applied
+	source.endpoints = []*endpoint.Endpoint{
+		{
+			DNSName:    "unchanged-record.used.tld",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4", "5.6.7.8"},
+		},
+	}
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, 3, provider.RecordsCallCount)
+	assert.Len(t, provider.ApplyChangesCalls, 1)
+
+	// Records are read again after changes were applied
+	require.NoError(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, 4, provider.RecordsCallCount)
+}
+
+func TestRunOnceReadsRecordsAfterApplyFailure(t *testing.T) {
+	source := &eventMockSource{endpoints: []*endpoint.Endpoint{
+		{
+			DNSName:    "create-record.used.tld",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4"},
+		},
+	}}
+	provider := &applyErrorMockProvider{err: errors.New("provider rejected changes")}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:              source,
+		Registry:            r,
+		Policy:              &plan.SyncPolicy{},
+		ManagedRecordTypes:  []string{endpoint.RecordTypeA},
+		MaxRecordsStaleness: 10 * time.Minute,
+	}
+
+	assert.Error(t, ctrl.RunOnce(context.Background()))
+	assert.Error(t, ctrl.RunOnce(context.Background()))
+	assert.Equal(t, 2, provider.RecordsCallCount)
+}
+
+// TestRunOnceKeepsApexRecordsOfUnresolvableTargets validates that an apex whose CNAME target can't be
+// resolved yet doesn't lead to the deletion of its existing records.
+func TestRunOnceKeepsApexRecordsOfUnresolvableTargets(t *testing.T) {
+	mockSource := new(testutils.MockSource)
+	mockSource.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("used.tld", endpoint.RecordTypeCNAME, "unresolvable.invalid"),
+	}, nil)
+	provider := &filteredMockProvider{
+		RecordsStore: []*endpoint.Endpoint{
+			endpoint.NewEndpoint("used.tld", endpoint.RecordTypeA, "1.2.3.4"),
+		},
+	}
+	r, err := registry.NewNoopRegistry(provider)
+	require.NoError(t, err)
+
+	ctrl := &Controller{
+		Source:             source.NewApexTargetSource(mockSource, []string{"used.tld"}),
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME},
+	}
+
+	assert.Error(t, ctrl.RunOnce(context.Background()))
+	assert.Empty(t, provider.ApplyChangesCalls)
+}
+
+// TestRunOnceCountsTargetMutations validates that only the changed targets of an updated record are
+// counted as mutations.
+func TestRunOnceCountsTargetMutations(t *testing.T) {
+	for _, tc := range []struct {
+		title           string
+		current         endpoint.Targets
+		desired         endpoint.Targets
+		expectedCreated float64
+		expectedDeleted float64
+	}{
+		{
+			title:           "add a single target",
+			current:         endpoint.Targets{"1.1.1.1", "1.1.1.2", "1.1.1.3", "1.1.1.4", "1.1.1.5"},
+			desired:         endpoint.Targets{"1.1.1.1", "1.1.1.2", "1.1.1.3", "1.1.1.4", "1.1.1.5", "1.1.1.6"},
+			expectedCreated: 1,
+		},
+		{
+			title:           "remove a single target",
+			current:         endpoint.Targets{"1.1.1.1", "1.1.1.2", "1.1.1.3"},
+			desired:         endpoint.Targets{"1.1.1.1", "1.1.1.3"},
+			expectedDeleted: 1,
+		},
+		{
+			title:           "swap a single target",
+			current:         endpoint.Targets{"1.1.1.1", "1.1.1.2", "1.1.1.3"},
+			desired:         endpoint.Targets{"1.1.1.1", "1.1.1.4", "1.1.1.3"},
+			expectedCreated: 1,
+			expectedDeleted: 1,
+		},
+		{
+			title:           "create a record",
+			desired:         endpoint.Targets{"1.1.1.1", "1.1.1.2"},
+			expectedCreated: 2,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			source := new(testutils.MockSource)
+			source.On("Endpoints").Return([]*endpoint.Endpoint{
+				endpoint.NewEndpoint("record.used.tld", endpoint.RecordTypeA, tc.desired...),
+			}, nil)
+			var records []*endpoint.Endpoint
+			if len(tc.current) > 0 {
+				records = append(records, endpoint.NewEndpoint("record.used.tld", endpoint.RecordTypeA, tc.current...))
+			}
+			provider := &filteredMockProvider{RecordsStore: records}
+			r, err := registry.NewNoopRegistry(provider)
+			require.NoError(t, err)
+
+			ctrl := &Controller{
+				Source:             source,
+				Registry:           r,
+				Policy:             &plan.SyncPolicy{},
+				ManagedRecordTypes: []string{endpoint.RecordTypeA},
+			}
+
+			created := testutil.ToFloat64(targetMutationsTotal.WithLabelValues("create"))
+			deleted := testutil.ToFloat64(targetMutationsTotal.WithLabelValues("delete"))
+			require.NoError(t, ctrl.RunOnce(context.Background()))
+			require.Len(t, provider.ApplyChangesCalls, 1)
+			assert.Equal(t, tc.expectedCreated, testutil.ToFloat64(targetMutationsTotal.WithLabelValues("create"))-created)
+			assert.Equal(t, tc.expectedDeleted, testutil.ToFloat64(targetMutationsTotal.WithLabelValues("delete"))-deleted)
+		})
+	}
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+)
+
+// DebugState keeps the desired endpoints and the plan of the most recent sync, serialized at the
+// time they were computed, so that they can be inspected without reading the DNS records again.
+type DebugState struct {
+	mu        sync.RWMutex
+	endpoints []byte
+	plan      []byte
+}
+
+// NewDebugState returns an empty DebugState.
+func NewDebugState() *DebugState {
+	return &DebugState{}
+}
+
+// Handler returns a read-only handler serving the desired endpoints on /debug/endpoints and the
+// plan on /debug/plan.
+func (s *DebugState) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/endpoints", func(w http.ResponseWriter, r *http.Request) {
+		s.serve(w, r, func() []byte { return s.endpoints })
+	})
+	mux.HandleFunc("/debug/plan", func(w http.ResponseWriter, r *http.Request) {
+		s.serve(w, r, func() []byte { return s.plan })
+	})
+	return mux
+}
+
+func (s *DebugState) serve(w http.ResponseWriter, r *http.Request, body func() []byte) {
+	if r.Method != http.MethodGet {
+		w.WriteHeader(http.StatusMethodNotAllowed)
+		return
+	}
+	s.mu.RLock()
+	b := body()
+	s.mu.RUnlock()
+	if b == nil {
+		http.Error(w, "not available before the first synchronization", http.StatusServiceUnavailable)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(b)
+}
+
+// setEndpoints records the endpoints desired by the sources.
+func (s *DebugState) setEndpoints(endpoints []*endpoint.Endpoint) {
+	if endpoints == nil {
+		endpoints = []*endpoint.Endpoint{}
+	}
+	b, err := json.Marshal(endpoints)
+	if err != nil {
+		log.Warnf("Failed to record the desired endpoints for debugging: %v", err)
+		return
+	}
+	s.mu.Lock()
+	s.endpoints = b
+	s.mu.Unlock()
+}
+
+// setPlan records the changes of a calculated plan.
+func (s *DebugState) setPlan(changes *plan.Changes) {
+	var buf bytes.Buffer
+	if err := plan.WriteChanges(&buf, changes); err != nil {
+		log.Warnf("Failed to record the plan for debugging: %v", err)
+		return
+	}
+	s.mu.Lock()
+	s.plan = buf.Bytes()
+	s.mu.Unlock()
+}
@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider/inmemory"
+	"sigs.k8s.io/external-dns/registry"
+	"sigs.k8s.io/external-dns/source"
+)
+
+// newLabelFilteredController returns a controller for the services labeled dns-team=<team>,
+// owning its records as <team>.
+func newLabelFilteredController(ctx context.Context, t *testing.T, client *fake.Clientset, p *inmemory.InMemoryProvider, team string) (*Controller, source.Source) {
+	t.Helper()
+
+	selector, err := labels.Parse("dns-team=" + team)
+	require.NoError(t, err)
+	src, err := source.NewServiceSource(ctx, client, "", "", "", false, "", false, false, false, []string{}, false, selector, v1.NodeExternalIP, labels.Everything(), source.LoadBalancerTargetBoth)
+	require.NoError(t, err)
+
+	managedRecordTypes := []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME}
+	r, err := registry.NewTXTRegistry(p, "", "", team, 0, "", managedRecordTypes, false)
+	require.NoError(t, err)
+
+	return &Controller{
+		Source:             src,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: managedRecordTypes,
+	}, src
+}
+
+// ownerOf returns the owner of the A record of name, or false if there is none.
+func ownerOf(t *testing.T, p *inmemory.InMemoryProvider, name string) (string, bool) {
+	t.Helper()
+
+	r, err := registry.NewTXTRegistry(p, "", "", "observer", 0, "", []string{endpoint.RecordTypeA}, false)
+	require.NoError(t, err)
+	records, err := r.Records(context.Background())
+	require.NoError(t, err)
+	for _, record := range records {
+		if record.DNSName == name && record.RecordType == endpoint.RecordTypeA {
+			return record.Labels[endpoint.OwnerLabelKey], true
+		}
+	}
+	return "", false
+}
+
+func TestRunOnceMovesRecordsBetweenLabelFilteredInstances(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client := fake.NewSimpleClientset()
+	p := inmemory.NewInMemoryProvider(inmemory.InMemoryInitZones([]string{"example.org"}))
+
+	svc := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "api",
+			Labels:      map[string]string{"dns-team": "payments"},
+			Annotations: map[string]string{"external-dns.alpha.kubernetes.io/hostname": "api.example.org"},
+		},
+		Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		Status: v1.ServiceStatus{
+			LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}}},
+		},
+	}
+	_, err := client.CoreV1().Services(svc.Namespace).Create(ctx, svc, metav1.CreateOptions{})
+	require.NoError(t, err)
+	// an unlabeled service is managed by neither instance
+	_, err = client.CoreV1().Services("default").Create(ctx, &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:   "default",
+			Name:        "unlabeled",
+			Annotations: map[string]string{"external-dns.alpha.kubernetes.io/hostname": "unlabeled.example.org"},
+		},
+		Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+		Status: v1.ServiceStatus{
+			LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "5.6.7.8"}}},
+		},
+	}, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	payments, paymentsSource := newLabelFilteredController(ctx, t, client, p, "payments")
+	orders, ordersSource := newLabelFilteredController(ctx, t, client, p, "orders")
+
+	require.NoError(t, payments.RunOnce(ctx))
+	require.NoError(t, orders.RunOnce(ctx))
+	owner, ok := ownerOf(t, p, "api.example.org")
+	require.True(t, ok)
+	assert.Equal(t, "payments", owner)
+	_, ok = ownerOf(t, p, "unlabeled.example.org")
+	assert.False(t, ok)
+
+	// Hand the service over to the other team.
+	svc.Labels["dns-team"] = "orders"
+	_, err = client.CoreV1().Services(svc.Namespace).Update(ctx, svc, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		paymentsEndpoints, err := paymentsSource.Endpoints(ctx)
+		require.NoError(t, err)
+		ordersEndpoints, err := ordersSource.Endpoints(ctx)
+		require.NoError(t, err)
+		return len(paymentsEndpoints) == 0 && len(ordersEndpoints) == 1
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// The new owner leaves the record of the old one alone until it has been deleted.
+	require.NoError(t, orders.RunOnce(ctx))
+	owner, ok = ownerOf(t, p, "api.example.org")
+	require.True(t, ok)
+	assert.Equal(t, "payments", owner)
+
+	require.NoError(t, payments.RunOnce(ctx))
+	_, ok = ownerOf(t, p, "api.example.org")
+	assert.False(t, ok)
+
+	require.NoError(t, orders.RunOnce(ctx))
+	owner, ok = ownerOf(t, p, "api.example.org")
+	require.True(t, ok)
+	assert.Equal(t, "orders", owner)
+}
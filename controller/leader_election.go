@@ -0,0 +1,165 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+)
+
+const (
+	// DefaultLeaseDuration is the duration standby instances wait before taking over a lease which is no longer renewed
+	DefaultLeaseDuration = 15 * time.Second
+	// DefaultRenewDeadline is the duration the leader retries renewing the lease before it stops leading
+	DefaultRenewDeadline = 10 * time.Second
+	// DefaultRetryPeriod is the duration between two attempts to acquire or renew the lease
+	DefaultRetryPeriod = 2 * time.Second
+)
+
+// errLeaseLost is returned when the leader lease could not be renewed.
+var errLeaseLost = errors.New("leader lease lost")
+
+// LeaderElectionConfig configures the Kubernetes lease based leader election.
+type LeaderElectionConfig struct {
+	Client    kubernetes.Interface
+	Namespace string
+	LeaseName string
+	// Identity uniquely identifies this instance among all candidates, e.g. the pod name
+	Identity      string
+	LeaseDuration time.Duration
+	RenewDeadline time.Duration
+	RetryPeriod   time.Duration
+}
+
+// RunWithLeaderElection runs the controller loop only while this instance holds the leader
+// lease. Standby instances wait until the lease is released or expires.
+//
+// When stop is closed the loop terminates as in RunUntil and the lease is only released once
+// the in-flight changes are applied, so a standby instance never applies changes concurrently
+// with this one. If the lease can't be renewed in time, the context of the in-flight changes
+// is canceled before the lease expires and an error is returned.
+func (c *Controller) RunWithLeaderElection(ctx context.Context, stop <-chan struct{}, cfg LeaderElectionConfig) error {
+	electionCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		mu       sync.Mutex
+		leading  bool
+		loopDone = make(chan struct{})
+	)
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock: &resourcelock.LeaseLock{
+			LeaseMeta: metav1.ObjectMeta{
+				Namespace: cfg.Namespace,
+				Name:      cfg.LeaseName,
+			},
+			Client: cfg.Client.CoordinationV1(),
+			LockConfig: resourcelock.ResourceLockConfig{
+				Identity: cfg.Identity,
+			},
+		},
+		LeaseDuration:   cfg.LeaseDuration,
+		RenewDeadline:   cfg.RenewDeadline,
+		RetryPeriod:     cfg.RetryPeriod,
+		ReleaseOnCancel: true,
+		Name:            cfg.LeaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				// Releasing the lease is deferred until the loop has terminated.
+				defer cancel()
+				defer close(loopDone)
+
+				mu.Lock()
+				leading = true
+				mu.Unlock()
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				log.Infof("Acquired leader lease %s/%s as %s", cfg.Namespace, cfg.LeaseName, cfg.Identity)
+
+				loopStop := make(chan struct{})
+				go func() {
+					select {
+					case <-stop:
+					case <-leaderCtx.Done():
+					}
+					close(loopStop)
+				}()
+				c.RunUntil(leaderCtx, loopStop)
+			},
+			OnStoppedLeading: func() {
+				mu.Lock()
+				defer mu.Unlock()
+				if leading {
+					log.Infof("Released leader lease %s/%s as %s", cfg.Namespace, cfg.LeaseName, cfg.Identity)
+				}
+			},
+			OnNewLeader: func(identity string) {
+				if identity != cfg.Identity {
+					log.Infof("Leader lease %s/%s is held by %s", cfg.Namespace, cfg.LeaseName, identity)
+				}
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// Standby instances have nothing to finish, they stop competing for the lease right away.
+	go func() {
+		select {
+		case <-stop:
+		case <-electionCtx.Done():
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		if !leading {
+			cancel()
+		}
+	}()
+
+	elector.Run(electionCtx)
+
+	mu.Lock()
+	wasLeading := leading
+	mu.Unlock()
+	if !wasLeading {
+		return nil
+	}
+	<-loopDone
+
+	select {
+	case <-stop:
+		return nil
+	case <-ctx.Done():
+		return nil
+	default:
+		return errLeaseLost
+	}
+}
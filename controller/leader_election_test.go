@@ -0,0 +1,194 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/internal/testutils"
+	"sigs.k8s.io/external-dns/plan"
+	"sigs.k8s.io/external-dns/provider"
+	"sigs.k8s.io/external-dns/registry"
+)
+
+// applyTracker records which instances applied changes and how many did so concurrently.
+type applyTracker struct {
+	mu          sync.Mutex
+	inFlight    int
+	maxInFlight int
+	applies     []string
+}
+
+func (t *applyTracker) appliedBy() []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return append([]string{}, t.applies...)
+}
+
+// trackingMockProvider always reports no records and tracks its ApplyChanges calls.
+type trackingMockProvider struct {
+	provider.BaseProvider
+	name    string
+	tracker *applyTracker
+}
+
+func (p *trackingMockProvider) Records(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	return nil, nil
+}
+
+func (p *trackingMockProvider) ApplyChanges(ctx context.Context, changes *plan.Changes) error {
+	p.tracker.mu.Lock()
+	p.tracker.inFlight++
+	if p.tracker.inFlight > p.tracker.maxInFlight {
+		p.tracker.maxInFlight = p.tracker.inFlight
+	}
+	p.tracker.applies = append(p.tracker.applies, p.name)
+	p.tracker.mu.Unlock()
+
+	time.Sleep(50 * time.Millisecond)
+
+	p.tracker.mu.Lock()
+	p.tracker.inFlight--
+	p.tracker.mu.Unlock()
+	return nil
+}
+
+func newLeaderElectionTestController(t *testing.T, name string, tracker *applyTracker) *Controller {
+	t.Helper()
+	source := new(testutils.MockSource)
+	source.On("Endpoints").Return([]*endpoint.Endpoint{
+		{
+			DNSName:    "create-record.used.tld",
+			RecordType: endpoint.RecordTypeA,
+			Targets:    endpoint.Targets{"1.2.3.4"},
+		},
+	}, nil)
+
+	r, err := registry.NewNoopRegistry(&trackingMockProvider{name: name, tracker: tracker})
+	require.NoError(t, err)
+
+	return &Controller{
+		Source:             source,
+		Registry:           r,
+		Policy:             &plan.SyncPolicy{},
+		ManagedRecordTypes: []string{endpoint.RecordTypeA},
+		Interval:           time.Millisecond,
+	}
+}
+
+func testLeaderElectionConfig(client kubernetes.Interface, identity string) LeaderElectionConfig {
+	return LeaderElectionConfig{
+		Client:        client,
+		Namespace:     "default",
+		LeaseName:     "external-dns",
+		Identity:      identity,
+		LeaseDuration: time.Second,
+		RenewDeadline: 500 * time.Millisecond,
+		RetryPeriod:   100 * time.Millisecond,
+	}
+}
+
+// TestRunWithLeaderElectionHandsOver validates that only the leader applies changes and that
+// the standby takes over without overlapping ApplyChanges calls once the leader stops.
+func TestRunWithLeaderElectionHandsOver(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	tracker := &applyTracker{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	leader := newLeaderElectionTestController(t, "leader", tracker)
+	stopLeader := make(chan struct{})
+	leaderErr := make(chan error, 1)
+	go func() {
+		leaderErr <- leader.RunWithLeaderElection(ctx, stopLeader, testLeaderElectionConfig(client, "leader"))
+	}()
+	require.Eventually(t, func() bool {
+		return len(tracker.appliedBy()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	standby := newLeaderElectionTestController(t, "standby", tracker)
+	stopStandby := make(chan struct{})
+	standbyErr := make(chan error, 1)
+	go func() {
+		standbyErr <- standby.RunWithLeaderElection(ctx, stopStandby, testLeaderElectionConfig(client, "standby"))
+	}()
+
+	// The standby must not apply anything while the leader holds the lease.
+	time.Sleep(1500 * time.Millisecond)
+	assert.NotContains(t, tracker.appliedBy(), "standby")
+
+	close(stopLeader)
+	require.NoError(t, <-leaderErr)
+	leaderApplies := len(tracker.appliedBy())
+
+	require.Eventually(t, func() bool {
+		return len(tracker.appliedBy()) > leaderApplies
+	}, 5*time.Second, 10*time.Millisecond)
+	close(stopStandby)
+	require.NoError(t, <-standbyErr)
+
+	applies := tracker.appliedBy()
+	for i, name := range applies {
+		if i < leaderApplies {
+			assert.Equal(t, "leader", name)
+		} else {
+			assert.Equal(t, "standby", name)
+		}
+	}
+	assert.Equal(t, 1, tracker.maxInFlight)
+}
+
+// TestRunWithLeaderElectionStandbyStops validates that a standby terminates right away when stopped.
+func TestRunWithLeaderElectionStandbyStops(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	tracker := &applyTracker{}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	leader := newLeaderElectionTestController(t, "leader", tracker)
+	stopLeader := make(chan struct{})
+	defer close(stopLeader)
+	go leader.RunWithLeaderElection(ctx, stopLeader, testLeaderElectionConfig(client, "leader"))
+	require.Eventually(t, func() bool {
+		return len(tracker.appliedBy()) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	standby := newLeaderElectionTestController(t, "standby", tracker)
+	stopStandby := make(chan struct{})
+	standbyErr := make(chan error, 1)
+	go func() {
+		standbyErr <- standby.RunWithLeaderElection(ctx, stopStandby, testLeaderElectionConfig(client, "standby"))
+	}()
+	close(stopStandby)
+
+	select {
+	case err := <-standbyErr:
+		assert.NoError(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("standby did not terminate after stop was closed")
+	}
+	assert.NotContains(t, tracker.appliedBy(), "standby")
+}
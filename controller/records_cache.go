@@ -0,0 +1,60 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// recordsCache holds the DNS records of the last sync which found nothing to change,
+// together with the endpoints the sources desired at that time.
+type recordsCache struct {
+	records       []*endpoint.Endpoint
+	endpointsHash string
+	readAt        time.Time
+}
+
+// valid returns true if the cached records can be reused for the given desired endpoints.
+func (rc *recordsCache) valid(endpointsHash string, now time.Time, maxStaleness time.Duration) bool {
+	return rc != nil && rc.endpointsHash == endpointsHash && now.Sub(rc.readAt) < maxStaleness
+}
+
+// hashEndpoints returns a digest of the given endpoints which doesn't depend on their order.
+func hashEndpoints(endpoints []*endpoint.Endpoint) (string, error) {
+	serialized := make([]string, 0, len(endpoints))
+	for _, ep := range endpoints {
+		b, err := json.Marshal(ep)
+		if err != nil {
+			return "", err
+		}
+		serialized = append(serialized, string(b))
+	}
+	sort.Strings(serialized)
+
+	h := sha256.New()
+	for _, s := range serialized {
+		h.Write([]byte(s))
+		h.Write([]byte{'\n'})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -30,6 +30,8 @@ import (
 const (
 	// RecordTypeA is a RecordType enum value
 	RecordTypeA = "A"
+	// RecordTypeAAAA is a RecordType enum value
+	RecordTypeAAAA = "AAAA"
 	// RecordTypeCNAME is a RecordType enum value
 	RecordTypeCNAME = "CNAME"
 	// RecordTypeTXT is a RecordType enum value
@@ -92,6 +94,51 @@ func (t Targets) Same(o Targets) bool {
 	return true
 }
 
+// SameForType compares two Targets of the given record type and returns true if they are
+// identical in their normalized form, see NormalizeTarget.
+func (t Targets) SameForType(o Targets, recordType string) bool {
+	if len(t) != len(o) {
+		return false
+	}
+
+	nt, no := t.normalized(recordType), o.normalized(recordType)
+	for i, e := range nt {
+		if e != no[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// normalized returns a sorted copy of the targets in their normalized form.
+func (t Targets) normalized(recordType string) []string {
+	n := make([]string, len(t))
+	for i, e := range t {
+		n[i] = NormalizeTarget(recordType, e)
+	}
+	sort.Strings(n)
+	return n
+}
+
+// NormalizeTarget returns the canonical form of a target of the given record type used for
+// comparisons. A and AAAA targets are trimmed and formatted canonically (e.g. 2001:DB8:0::1
+// becomes 2001:db8::1), CNAME and NS targets are trimmed and lowercased. Targets of other
+// record types, like TXT, are returned unchanged as their case and whitespace are significant.
+func NormalizeTarget(recordType, target string) string {
+	switch recordType {
+	case RecordTypeA, RecordTypeAAAA:
+		target = strings.TrimSpace(target)
+		if ip, err := netip.ParseAddr(target); err == nil {
+			return ip.String()
+		}
+		return target
+	case RecordTypeCNAME, RecordTypeNS:
+		return strings.ToLower(strings.TrimSpace(target))
+	default:
+		return target
+	}
+}
+
 // IsLess should fulfill the requirement to compare two targets and choose the 'lesser' one.
 // In the past target was a simple string so simple string comparison could be used. Now we define 'less'
 // as either being the shorter list of targets or where the first entry is less.
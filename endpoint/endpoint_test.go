@@ -50,6 +50,81 @@ func TestTargetsSame(t *testing.T) {
 	}
 }
 
+func TestTargetsSameForType(t *testing.T) {
+	tests := []struct {
+		recordType string
+		a          Targets
+		b          Targets
+	}{
+		{
+			RecordTypeCNAME,
+			[]string{"LB.Example.ORG"},
+			[]string{"lb.example.org"},
+		}, {
+			RecordTypeAAAA,
+			[]string{"2001:DB8::1"},
+			[]string{"2001:db8::1"},
+		}, {
+			RecordTypeAAAA,
+			[]string{"2001:db8:0:0:0:0:0:1", "2001:db8::2"},
+			[]string{"2001:db8::2", "2001:db8::1"},
+		}, {
+			RecordTypeA,
+			[]string{" 1.2.3.4 ", "1.2.3.5\t"},
+			[]string{"1.2.3.4", "1.2.3.5"},
+		}, {
+			RecordTypeNS,
+			[]string{"B.example.org", "a.example.org"},
+			[]string{"b.example.org", "A.example.org"},
+		}, {
+			RecordTypeTXT,
+			[]string{"heritage=external-dns,external-dns/owner=Owner"},
+			[]string{"heritage=external-dns,external-dns/owner=Owner"},
+		},
+	}
+
+	for _, d := range tests {
+		if d.a.SameForType(d.b, d.recordType) != true {
+			t.Errorf("%#v should equal %#v", d.a, d.b)
+		}
+		if d.b.SameForType(d.a, d.recordType) != true {
+			t.Errorf("%#v should equal %#v", d.b, d.a)
+		}
+	}
+}
+
+func TestTargetsSameForTypeTXT(t *testing.T) {
+	a := Targets{"heritage=external-dns,external-dns/owner=Owner"}
+	b := Targets{" heritage=external-dns,external-dns/owner=owner "}
+	if a.SameForType(b, RecordTypeTXT) {
+		t.Errorf("%#v should not equal %#v", a, b)
+	}
+}
+
+func TestNormalizeTarget(t *testing.T) {
+	tests := []struct {
+		recordType string
+		target     string
+		expected   string
+	}{
+		{RecordTypeA, "1.2.3.4", "1.2.3.4"},
+		{RecordTypeA, " 1.2.3.4 ", "1.2.3.4"},
+		{RecordTypeAAAA, "2001:DB8::1", "2001:db8::1"},
+		{RecordTypeAAAA, "2001:db8:0:0:0:0:0:1", "2001:db8::1"},
+		{RecordTypeCNAME, "Foo.Example.ORG", "foo.example.org"},
+		{RecordTypeCNAME, "  foo.example.org  ", "foo.example.org"},
+		{RecordTypeNS, "NS1.Example.ORG", "ns1.example.org"},
+		{RecordTypeTXT, " Some Text ", " Some Text "},
+		{RecordTypeTXT, "heritage=external-dns,external-dns/owner=Owner", "heritage=external-dns,external-dns/owner=Owner"},
+	}
+
+	for _, tt := range tests {
+		if got := NormalizeTarget(tt.recordType, tt.target); got != tt.expected {
+			t.Errorf("NormalizeTarget(%q, %q) = %q, expected %q", tt.recordType, tt.target, got, tt.expected)
+		}
+	}
+}
+
 func TestSameFailures(t *testing.T) {
 	tests := []struct {
 		a Targets
@@ -18,6 +18,8 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"os/signal"
@@ -102,15 +104,20 @@ func main() {
 
 	ctx, cancel := context.WithCancel(context.Background())
 
+	stop := make(chan struct{})
+
 	go serveMetrics(cfg.MetricsAddress)
-	go handleSigterm(cancel)
+	go handleSigterm(stop, cancel, cfg.ShutdownGracePeriod)
 
 	// error is explicitly ignored because the filter is already validated in validation.ValidateConfig
 	labelSelector, _ := labels.Parse(cfg.LabelFilter)
+	nodePortLabelSelector, _ := labels.Parse(cfg.NodePortLabelFilter)
+	nodeLabelSelector, _ := labels.Parse(cfg.NodeLabelFilter)
+	podLabelSelector, _ := labels.Parse(cfg.PodLabelFilter)
 
 	// Create a source.Config from the flags passed by the user.
 	sourceCfg := &source.Config{
-		Namespace:                      cfg.Namespace,
+		Namespaces:                     cfg.Namespaces,
 		AnnotationFilter:               cfg.AnnotationFilter,
 		LabelFilter:                    labelSelector,
 		FQDNTemplate:                   cfg.FQDNTemplate,
@@ -118,6 +125,15 @@ func main() {
 		IgnoreHostnameAnnotation:       cfg.IgnoreHostnameAnnotation,
 		IgnoreIngressTLSSpec:           cfg.IgnoreIngressTLSSpec,
 		IgnoreIngressRulesSpec:         cfg.IgnoreIngressRulesSpec,
+		IngressClassNames:              cfg.IngressClassNames,
+		NodeAddressPreference:          cfg.NodeAddressPreference,
+		NodePortLabelFilter:            nodePortLabelSelector,
+		NodeLabelFilter:                nodeLabelSelector,
+		PodLabelFilter:                 podLabelSelector,
+		PreferLBTarget:                 cfg.PreferLBTarget,
+		PodFQDNTemplate:                cfg.PodFQDNTemplate,
+		ServiceImportFQDNTemplate:      cfg.ServiceImportFQDNTemplate,
+		PodSourceTTL:                   cfg.PodSourceTTL,
 		GatewayNamespace:               cfg.GatewayNamespace,
 		GatewayLabelFilter:             cfg.GatewayLabelFilter,
 		Compatibility:                  cfg.Compatibility,
@@ -136,13 +152,14 @@ func main() {
 		ContourLoadBalancerService:     cfg.ContourLoadBalancerService,
 		GlooNamespace:                  cfg.GlooNamespace,
 		SkipperRouteGroupVersion:       cfg.SkipperRouteGroupVersion,
+		TraefikService:                 cfg.TraefikService,
+		KongProxyService:               cfg.KongProxyService,
 		RequestTimeout:                 cfg.RequestTimeout,
 		DefaultTargets:                 cfg.DefaultTargets,
 		OCPRouterName:                  cfg.OCPRouterName,
 	}
 
-	// Lookup all the selected sources by names and pass them the desired configuration.
-	sources, err := source.ByNames(ctx, &source.SingletonClientGenerator{
+	clientGenerator := &source.SingletonClientGenerator{
 		KubeConfig:   cfg.KubeConfig,
 		APIServerURL: cfg.APIServerURL,
 		// If update events are enabled, disable timeout.
@@ -152,7 +169,10 @@ func main() {
 			}
 			return cfg.RequestTimeout
 		}(),
-	}, cfg.Sources, sourceCfg)
+	}
+
+	// Lookup all the selected sources by names and pass them the desired configuration.
+	sources, err := source.ByNames(ctx, clientGenerator, cfg.Sources, sourceCfg)
 	if err != nil {
 		log.Fatal(err)
 	}
@@ -162,6 +182,9 @@ func main() {
 
 	// Combine multiple sources into a single, deduplicated source.
 	endpointsSource := source.NewDedupSource(source.NewMultiSource(sources, sourceCfg.DefaultTargets))
+	if len(cfg.ResolveCNAMETargetsAtApex) > 0 {
+		endpointsSource = source.NewApexTargetSource(endpointsSource, cfg.ResolveCNAMETargetsAtApex)
+	}
 	endpointsSource = source.NewTargetFilterSource(endpointsSource, targetFilter)
 
 	// RegexDomainFilter overrides DomainFilter
@@ -366,7 +389,7 @@ func main() {
 	case "noop":
 		r, err = registry.NewNoopRegistry(p)
 	case "txt":
-		r, err = registry.NewTXTRegistry(p, cfg.TXTPrefix, cfg.TXTSuffix, cfg.TXTOwnerID, cfg.TXTCacheInterval, cfg.TXTWildcardReplacement, cfg.ManagedDNSRecordTypes)
+		r, err = registry.NewTXTRegistry(p, cfg.TXTPrefix, cfg.TXTSuffix, cfg.TXTOwnerID, cfg.TXTCacheInterval, cfg.TXTWildcardReplacement, cfg.ManagedDNSRecordTypes, cfg.TXTFailOnOwnerConflict)
 	case "aws-sd":
 		r, err = registry.NewAWSSDRegistry(p.(*awssd.AWSSDProvider), cfg.TXTOwnerID)
 	default:
@@ -382,6 +405,11 @@ func main() {
 		log.Fatalf("unknown policy: %s", cfg.Policy)
 	}
 
+	planOutput, err := openPlanOutput(cfg.PlanOutput, cfg.DryRun)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	ctrl := controller.Controller{
 		Source:               endpointsSource,
 		Registry:             r,
@@ -390,6 +418,14 @@ func main() {
 		DomainFilter:         domainFilter,
 		ManagedRecordTypes:   cfg.ManagedDNSRecordTypes,
 		MinEventSyncInterval: cfg.MinEventSyncInterval,
+		IntervalJitter:       cfg.IntervalJitter,
+		MaxRecordsStaleness:  cfg.MaxRecordsStaleness,
+		PlanOutput:           planOutput,
+		DryRun:               cfg.DryRun,
+	}
+	if cfg.ExposeDebugEndpoints {
+		ctrl.Debug = controller.NewDebugState()
+		http.Handle("/debug/", ctrl.Debug.Handler())
 	}
 
 	if cfg.Once {
@@ -409,14 +445,63 @@ func main() {
 	}
 
 	ctrl.ScheduleRunOnce(time.Now())
-	ctrl.Run(ctx)
+
+	if cfg.LeaderElection {
+		kubeClient, err := clientGenerator.KubeClient()
+		if err != nil {
+			log.Fatal(err)
+		}
+		identity, err := os.Hostname()
+		if err != nil {
+			log.Fatalf("failed to determine leader election identity: %v", err)
+		}
+		err = ctrl.RunWithLeaderElection(ctx, stop, controller.LeaderElectionConfig{
+			Client:        kubeClient,
+			Namespace:     cfg.LeaderElectionNamespace,
+			LeaseName:     cfg.LeaderElectionLeaseName,
+			Identity:      identity,
+			LeaseDuration: controller.DefaultLeaseDuration,
+			RenewDeadline: controller.DefaultRenewDeadline,
+			RetryPeriod:   controller.DefaultRetryPeriod,
+		})
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	ctrl.RunUntil(ctx, stop)
+}
+
+// openPlanOutput returns the writer calculated plans are serialized to. In dry-run
+// mode the plan is the primary output, so it defaults to stdout.
+func openPlanOutput(path string, dryRun bool) (io.Writer, error) {
+	if path == "" && dryRun {
+		path = "-"
+	}
+	switch path {
+	case "":
+		return nil, nil
+	case "-":
+		return os.Stdout, nil
+	default:
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open plan output: %w", err)
+		}
+		return f, nil
+	}
 }
 
-func handleSigterm(cancel func()) {
+// handleSigterm stops the controller loop on SIGTERM and gives an in-flight synchronization
+// up to gracePeriod to finish before its context is canceled.
+func handleSigterm(stop chan struct{}, cancel func(), gracePeriod time.Duration) {
 	signals := make(chan os.Signal, 1)
 	signal.Notify(signals, syscall.SIGTERM)
 	<-signals
-	log.Info("Received SIGTERM. Terminating...")
+	log.Infof("Received SIGTERM. Terminating after in-flight changes are applied (at most %s)...", gracePeriod)
+	close(stop)
+	time.Sleep(gracePeriod)
 	cancel()
 }
 
@@ -49,8 +49,10 @@ type Config struct {
 	ContourLoadBalancerService        string
 	GlooNamespace                     string
 	SkipperRouteGroupVersion          string
+	TraefikService                    string
+	KongProxyService                  string
 	Sources                           []string
-	Namespace                         string
+	Namespaces                        []string
 	AnnotationFilter                  string
 	LabelFilter                       string
 	FQDNTemplate                      string
@@ -58,6 +60,15 @@ type Config struct {
 	IgnoreHostnameAnnotation          bool
 	IgnoreIngressTLSSpec              bool
 	IgnoreIngressRulesSpec            bool
+	IngressClassNames                 []string
+	NodeAddressPreference             string
+	NodePortLabelFilter               string
+	NodeLabelFilter                   string
+	PodLabelFilter                    string
+	PreferLBTarget                    string
+	PodFQDNTemplate                   string
+	ServiceImportFQDNTemplate         string
+	PodSourceTTL                      time.Duration
 	GatewayNamespace                  string
 	GatewayLabelFilter                string
 	Compatibility                     string
@@ -78,6 +89,7 @@ type Config struct {
 	ZoneIDFilter                      []string
 	TargetNetFilter                   []string
 	ExcludeTargetNets                 []string
+	ResolveCNAMETargetsAtApex         []string
 	AlibabaCloudConfigFile            string
 	AlibabaCloudZoneType              string
 	AWSZoneType                       string
@@ -144,15 +156,24 @@ type Config struct {
 	TXTPrefix                         string
 	TXTSuffix                         string
 	Interval                          time.Duration
+	IntervalJitter                    float64
+	MaxRecordsStaleness               time.Duration
 	MinEventSyncInterval              time.Duration
+	ShutdownGracePeriod               time.Duration
+	LeaderElection                    bool
+	LeaderElectionNamespace           string
+	LeaderElectionLeaseName           string
 	Once                              bool
 	DryRun                            bool
+	PlanOutput                        string
 	UpdateEvents                      bool
 	LogFormat                         string
 	MetricsAddress                    string
+	ExposeDebugEndpoints              bool
 	LogLevel                          string
 	TXTCacheInterval                  time.Duration
 	TXTWildcardReplacement            string
+	TXTFailOnOwnerConflict            bool
 	ExoscaleEndpoint                  string
 	ExoscaleAPIKey                    string `secure:"yes"`
 	ExoscaleAPISecret                 string `secure:"yes"`
@@ -207,8 +228,10 @@ var defaultConfig = &Config{
 	ContourLoadBalancerService:  "heptio-contour/contour",
 	GlooNamespace:               "gloo-system",
 	SkipperRouteGroupVersion:    "zalando.org/v1",
+	TraefikService:              "",
+	KongProxyService:            "",
 	Sources:                     nil,
-	Namespace:                   "",
+	Namespaces:                  []string{},
 	AnnotationFilter:            "",
 	LabelFilter:                 labels.Everything().String(),
 	FQDNTemplate:                "",
@@ -216,6 +239,15 @@ var defaultConfig = &Config{
 	IgnoreHostnameAnnotation:    false,
 	IgnoreIngressTLSSpec:        false,
 	IgnoreIngressRulesSpec:      false,
+	IngressClassNames:           []string{},
+	NodeAddressPreference:       "ExternalIP",
+	NodePortLabelFilter:         labels.Everything().String(),
+	NodeLabelFilter:             labels.Everything().String(),
+	PodLabelFilter:              labels.Everything().String(),
+	PreferLBTarget:              source.LoadBalancerTargetBoth,
+	PodFQDNTemplate:             "",
+	ServiceImportFQDNTemplate:   "",
+	PodSourceTTL:                60 * time.Second,
 	GatewayNamespace:            "",
 	GatewayLabelFilter:          "",
 	Compatibility:               "",
@@ -234,6 +266,7 @@ var defaultConfig = &Config{
 	RegexDomainExclusion:        regexp.MustCompile(""),
 	TargetNetFilter:             []string{},
 	ExcludeTargetNets:           []string{},
+	ResolveCNAMETargetsAtApex:   []string{},
 	AlibabaCloudConfigFile:      "/etc/kubernetes/alibaba-cloud.json",
 	AWSZoneType:                 "",
 	AWSZoneTagFilter:            []string{},
@@ -289,13 +322,22 @@ var defaultConfig = &Config{
 	TXTSuffix:                   "",
 	TXTCacheInterval:            0,
 	TXTWildcardReplacement:      "",
+	TXTFailOnOwnerConflict:      false,
 	MinEventSyncInterval:        5 * time.Second,
 	Interval:                    time.Minute,
+	IntervalJitter:              0,
+	MaxRecordsStaleness:         0,
+	ShutdownGracePeriod:         20 * time.Second,
+	LeaderElection:              false,
+	LeaderElectionNamespace:     "default",
+	LeaderElectionLeaseName:     "external-dns",
 	Once:                        false,
 	DryRun:                      false,
+	PlanOutput:                  "",
 	UpdateEvents:                false,
 	LogFormat:                   "text",
 	MetricsAddress:              ":7979",
+	ExposeDebugEndpoints:        false,
 	LogLevel:                    logrus.InfoLevel.String(),
 	ExoscaleEndpoint:            "https://api.exoscale.ch/dns",
 	ExoscaleAPIKey:              "",
@@ -401,12 +443,16 @@ func (cfg *Config) ParseFlags(args []string) error {
 	// Flags related to Skipper RouteGroup
 	app.Flag("skipper-routegroup-groupversion", "The resource version for skipper routegroup").Default(source.DefaultRoutegroupVersion).StringVar(&cfg.SkipperRouteGroupVersion)
 
+	// Flags related to Traefik
+	app.Flag("traefik-service", "The namespace/name of the Service exposing Traefik, its load balancer addresses are used as targets; valid only when using traefik-proxy source").Default(defaultConfig.TraefikService).StringVar(&cfg.TraefikService)
+	app.Flag("kong-proxy-service", "The namespace/name of the Service exposing the Kong proxy, its load balancer addresses are used as targets of the TCPIngresses and UDPIngresses without any; valid only when using kong-tcpingress or kong-udpingress source").Default(defaultConfig.KongProxyService).StringVar(&cfg.KongProxyService)
+
 	// Flags related to processing source
-	app.Flag("source", "The resource types that are queried for endpoints; specify multiple times for multiple sources (required, options: service, ingress, node, fake, connector, gateway-httproute, gateway-tlsroute, gateway-tcproute, gateway-udproute, istio-gateway, istio-virtualservice, cloudfoundry, contour-ingressroute, contour-httpproxy, gloo-proxy, crd, empty, skipper-routegroup, openshift-route, ambassador-host, kong-tcpingress)").Required().PlaceHolder("source").EnumsVar(&cfg.Sources, "service", "ingress", "node", "pod", "gateway-httproute", "gateway-tlsroute", "gateway-tcproute", "gateway-udproute", "istio-gateway", "istio-virtualservice", "cloudfoundry", "contour-ingressroute", "contour-httpproxy", "gloo-proxy", "fake", "connector", "crd", "empty", "skipper-routegroup", "openshift-route", "ambassador-host", "kong-tcpingress")
+	app.Flag("source", "The resource types that are queried for endpoints; specify multiple times for multiple sources (required, options: service, ingress, node, fake, connector, gateway-httproute, gateway-tlsroute, gateway-tcproute, gateway-udproute, istio-gateway, istio-virtualservice, cloudfoundry, contour-ingressroute, contour-httpproxy, gloo-proxy, crd, empty, skipper-routegroup, openshift-route, ambassador-host, kong-tcpingress, kong-udpingress, serviceimport, traefik-proxy)").Required().PlaceHolder("source").EnumsVar(&cfg.Sources, "service", "ingress", "node", "pod", "gateway-httproute", "gateway-tlsroute", "gateway-tcproute", "gateway-udproute", "istio-gateway", "istio-virtualservice", "cloudfoundry", "contour-ingressroute", "contour-httpproxy", "gloo-proxy", "fake", "connector", "crd", "empty", "skipper-routegroup", "openshift-route", "ambassador-host", "kong-tcpingress", "kong-udpingress", "serviceimport", "traefik-proxy")
 	app.Flag("openshift-router-name", "if source is openshift-route then you can pass the ingress controller name. Based on this name external-dns will select the respective router from the route status and map that routerCanonicalHostname to the route host while creating a CNAME record.").StringVar(&cfg.OCPRouterName)
-	app.Flag("namespace", "Limit sources of endpoints to a specific namespace (default: all namespaces)").Default(defaultConfig.Namespace).StringVar(&cfg.Namespace)
+	app.Flag("namespace", "Limit sources of endpoints to the given namespaces; specify multiple times or comma separated for multiple namespaces (default: all namespaces)").StringsVar(&cfg.Namespaces)
 	app.Flag("annotation-filter", "Filter sources managed by external-dns via annotation using label selector semantics (default: all sources)").Default(defaultConfig.AnnotationFilter).StringVar(&cfg.AnnotationFilter)
-	app.Flag("label-filter", "Filter sources managed by external-dns via label selector when listing all resources; currently supported by source types CRD, ingress, service and openshift-route").Default(defaultConfig.LabelFilter).StringVar(&cfg.LabelFilter)
+	app.Flag("label-filter", "Filter sources managed by external-dns via label selector when listing all resources; currently supported by source types CRD, ingress, service, openshift-route and the gateway route sources").Default(defaultConfig.LabelFilter).StringVar(&cfg.LabelFilter)
 	app.Flag("fqdn-template", "A templated string that's used to generate DNS names from sources that don't define a hostname themselves, or to add a hostname suffix when paired with the fake source (optional). Accepts comma separated list for multiple global FQDN.").Default(defaultConfig.FQDNTemplate).StringVar(&cfg.FQDNTemplate)
 	app.Flag("combine-fqdn-annotation", "Combine FQDN template and Annotations instead of overwriting").BoolVar(&cfg.CombineFQDNAndAnnotation)
 	app.Flag("ignore-hostname-annotation", "Ignore hostname annotation when generating DNS names, valid only when using fqdn-template is set (optional, default: false)").BoolVar(&cfg.IgnoreHostnameAnnotation)
@@ -415,6 +461,15 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("gateway-label-filter", "Filter Gateways of Route endpoints via label selector (default: all gateways)").StringVar(&cfg.GatewayLabelFilter)
 	app.Flag("compatibility", "Process annotation semantics from legacy implementations (optional, options: mate, molecule, kops-dns-controller)").Default(defaultConfig.Compatibility).EnumVar(&cfg.Compatibility, "", "mate", "molecule", "kops-dns-controller")
 	app.Flag("ignore-ingress-rules-spec", "Ignore rules spec section in ingresses resources, applicable only for ingress sources (optional, default: false)").BoolVar(&cfg.IgnoreIngressRulesSpec)
+	app.Flag("ingress-class", "Limit ingresses to the given ingress class, matching spec.ingressClassName or the kubernetes.io/ingress.class annotation; specify multiple times for multiple classes, an empty class matches ingresses without a class (optional, default: all ingress classes)").StringsVar(&cfg.IngressClassNames)
+	app.Flag("node-address-preference", "The type of node addresses to publish, falling back to the other type if a node has none, applicable for node sources and NodePort services (default: ExternalIP, options: ExternalIP, InternalIP)").Default(defaultConfig.NodeAddressPreference).EnumVar(&cfg.NodeAddressPreference, "ExternalIP", "InternalIP")
+	app.Flag("node-port-label-filter", "Limit the nodes whose addresses are published for NodePort services by a label selector (default: all nodes)").Default(defaultConfig.NodePortLabelFilter).StringVar(&cfg.NodePortLabelFilter)
+	app.Flag("node-label-filter", "Limit the nodes published by node sources by a label selector, --label-filter doesn't apply to them (default: all nodes)").Default(defaultConfig.NodeLabelFilter).StringVar(&cfg.NodeLabelFilter)
+	app.Flag("prefer-lb-target", "Which addresses of a load balancer reporting both an IP and a hostname become targets, for service, ingress, Kong and Traefik sources; ip and hostname fall back to the other one if missing (default: both, options: both, ip, hostname)").Default(defaultConfig.PreferLBTarget).EnumVar(&cfg.PreferLBTarget, source.LoadBalancerTargetBoth, source.LoadBalancerTargetIP, source.LoadBalancerTargetHostname)
+	app.Flag("pod-fqdn-template", "A templated string that's used to publish every ready hostNetwork pod matching --pod-label-filter with its host IP, e.g. edge-{{.Spec.NodeName}}.example.com, applicable only for pod sources (optional)").Default(defaultConfig.PodFQDNTemplate).StringVar(&cfg.PodFQDNTemplate)
+	app.Flag("pod-label-filter", "Limit the pods published with --pod-fqdn-template by a label selector, --label-filter doesn't apply to them (default: all pods)").Default(defaultConfig.PodLabelFilter).StringVar(&cfg.PodLabelFilter)
+	app.Flag("serviceimport-fqdn-template", "A templated string that's used to generate DNS names from ServiceImports without a hostname annotation, e.g. {{.Name}}.{{.Namespace}}.clusterset.example.com, applicable only for serviceimport sources (optional)").Default(defaultConfig.ServiceImportFQDNTemplate).StringVar(&cfg.ServiceImportFQDNTemplate)
+	app.Flag("pod-source-ttl", "The TTL of records published for hostNetwork pods with the pod FQDN template, applicable only for pod sources (default: 1m)").Default(defaultConfig.PodSourceTTL.String()).DurationVar(&cfg.PodSourceTTL)
 	app.Flag("publish-internal-services", "Allow external-dns to publish DNS records for ClusterIP services (optional)").BoolVar(&cfg.PublishInternal)
 	app.Flag("publish-host-ip", "Allow external-dns to publish host-ip for headless services (optional)").BoolVar(&cfg.PublishHostIP)
 	app.Flag("always-publish-not-ready-addresses", "Always publish also not ready addresses for headless services (optional)").BoolVar(&cfg.AlwaysPublishNotReadyAddresses)
@@ -422,10 +477,11 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("crd-source-apiversion", "API version of the CRD for crd source, e.g. `externaldns.k8s.io/v1alpha1`, valid only when using crd source").Default(defaultConfig.CRDSourceAPIVersion).StringVar(&cfg.CRDSourceAPIVersion)
 	app.Flag("crd-source-kind", "Kind of the CRD for the crd source in API group and version specified by crd-source-apiversion").Default(defaultConfig.CRDSourceKind).StringVar(&cfg.CRDSourceKind)
 	app.Flag("service-type-filter", "The service types to take care about (default: all, expected: ClusterIP, NodePort, LoadBalancer or ExternalName)").StringsVar(&cfg.ServiceTypeFilter)
-	app.Flag("managed-record-types", "Comma separated list of record types to manage (default: A, CNAME) (supported records: CNAME, A, NS").Default("A", "CNAME").StringsVar(&cfg.ManagedDNSRecordTypes)
+	app.Flag("managed-record-types", "Comma separated list of record types to manage (default: A, CNAME) (supported records: CNAME, A, AAAA, NS").Default("A", "CNAME").StringsVar(&cfg.ManagedDNSRecordTypes)
 	app.Flag("default-targets", "Set globally default IP address that will apply as a target instead of source addresses. Specify multiple times for multiple targets (optional)").StringsVar(&cfg.DefaultTargets)
 	app.Flag("target-net-filter", "Limit possible targets by a net filter; specify multiple times for multiple possible nets (optional)").StringsVar(&cfg.TargetNetFilter)
 	app.Flag("exclude-target-net", "Exclude target nets (optional)").StringsVar(&cfg.ExcludeTargetNets)
+	app.Flag("resolve-cname-targets-at-apex", "Resolve the hostname targets of CNAME records at the given zone apex to A and AAAA records on each sync (optional, specify multiple times for multiple zones)").StringsVar(&cfg.ResolveCNAMETargetsAtApex)
 
 	// Flags related to providers
 	app.Flag("provider", "The DNS provider where the DNS records will be created (required, options: aws, aws-sd, godaddy, google, azure, azure-dns, azure-private-dns, bluecat, cloudflare, rcodezero, digitalocean, dnsimple, akamai, infoblox, dyn, designate, coredns, skydns, ibmcloud, inmemory, ovh, pdns, oci, exoscale, linode, rfc2136, ns1, transip, vinyldns, rdns, scaleway, vultr, ultradns, gandi, safedns, tencentcloud)").Required().PlaceHolder("provider").EnumVar(&cfg.Provider, "aws", "aws-sd", "google", "azure", "azure-dns", "azure-private-dns", "alibabacloud", "civo", "cloudflare", "rcodezero", "digitalocean", "dnsimple", "akamai", "infoblox", "dyn", "designate", "coredns", "skydns", "ibmcloud", "inmemory", "ovh", "pdns", "oci", "exoscale", "linode", "rfc2136", "ns1", "transip", "vinyldns", "rdns", "scaleway", "vultr", "ultradns", "godaddy", "bluecat", "gandi", "safedns", "tencentcloud", "pihole", "plural")
@@ -560,18 +616,27 @@ func (cfg *Config) ParseFlags(args []string) error {
 	app.Flag("txt-prefix", "When using the TXT registry, a custom string that's prefixed to each ownership DNS record (optional). Could contain record type template like '%{record_type}-prefix-'. Mutual exclusive with txt-suffix!").Default(defaultConfig.TXTPrefix).StringVar(&cfg.TXTPrefix)
 	app.Flag("txt-suffix", "When using the TXT registry, a custom string that's suffixed to the host portion of each ownership DNS record (optional). Could contain record type template like '-%{record_type}-suffix'. Mutual exclusive with txt-prefix!").Default(defaultConfig.TXTSuffix).StringVar(&cfg.TXTSuffix)
 	app.Flag("txt-wildcard-replacement", "When using the TXT registry, a custom string that's used instead of an asterisk for TXT records corresponding to wildcard DNS records (optional)").Default(defaultConfig.TXTWildcardReplacement).StringVar(&cfg.TXTWildcardReplacement)
+	app.Flag("txt-fail-on-owner-conflict", "When using the TXT registry, fail the synchronization instead of only logging a warning if a record is claimed by this instance and another owner (default: disabled)").BoolVar(&cfg.TXTFailOnOwnerConflict)
 
 	// Flags related to the main control loop
 	app.Flag("txt-cache-interval", "The interval between cache synchronizations in duration format (default: disabled)").Default(defaultConfig.TXTCacheInterval.String()).DurationVar(&cfg.TXTCacheInterval)
 	app.Flag("interval", "The interval between two consecutive synchronizations in duration format (default: 1m)").Default(defaultConfig.Interval.String()).DurationVar(&cfg.Interval)
+	app.Flag("interval-jitter", "Randomly shorten or lengthen every synchronization interval by up to this fraction of --interval to spread out instances with the same interval, e.g. 0.2 for up to 20% (default: 0, disabled)").Default(strconv.FormatFloat(defaultConfig.IntervalJitter, 'f', -1, 64)).Float64Var(&cfg.IntervalJitter)
+	app.Flag("max-records-staleness", "Skip reading the DNS records from the provider while the sources yield the same endpoints as in the last synchronization which found nothing to change, for at most this duration (default: 0, disabled)").Default(defaultConfig.MaxRecordsStaleness.String()).DurationVar(&cfg.MaxRecordsStaleness)
 	app.Flag("min-event-sync-interval", "The minimum interval between two consecutive synchronizations triggered from kubernetes events in duration format (default: 5s)").Default(defaultConfig.MinEventSyncInterval.String()).DurationVar(&cfg.MinEventSyncInterval)
+	app.Flag("shutdown-grace-period", "The maximum time an in-flight synchronization may take to finish after SIGTERM before it is aborted; keep it below the pod's terminationGracePeriodSeconds (default: 20s)").Default(defaultConfig.ShutdownGracePeriod.String()).DurationVar(&cfg.ShutdownGracePeriod)
+	app.Flag("leader-election", "When enabled, only the instance holding a Kubernetes lease runs the synchronization loop while other replicas stand by (default: disabled)").BoolVar(&cfg.LeaderElection)
+	app.Flag("leader-election-namespace", "When using leader election, the namespace of the lease (default: default)").Default(defaultConfig.LeaderElectionNamespace).StringVar(&cfg.LeaderElectionNamespace)
+	app.Flag("leader-election-lease-name", "When using leader election, the name of the lease; replicas sharing a lease must have the same configuration (default: external-dns)").Default(defaultConfig.LeaderElectionLeaseName).StringVar(&cfg.LeaderElectionLeaseName)
 	app.Flag("once", "When enabled, exits the synchronization loop after the first iteration (default: disabled)").BoolVar(&cfg.Once)
 	app.Flag("dry-run", "When enabled, prints DNS record changes rather than actually performing them (default: disabled)").BoolVar(&cfg.DryRun)
+	app.Flag("plan-output", "When set, writes every calculated plan as versioned JSON to the given file before it is applied; use '-' for stdout (default: disabled, stdout when --dry-run is enabled)").Default(defaultConfig.PlanOutput).StringVar(&cfg.PlanOutput)
 	app.Flag("events", "When enabled, in addition to running every interval, the reconciliation loop will get triggered when supported sources change (default: disabled)").BoolVar(&cfg.UpdateEvents)
 
 	// Miscellaneous flags
 	app.Flag("log-format", "The format in which log messages are printed (default: text, options: text, json)").Default(defaultConfig.LogFormat).EnumVar(&cfg.LogFormat, "text", "json")
 	app.Flag("metrics-address", "Specify where to serve the metrics and health check endpoint (default: :7979)").Default(defaultConfig.MetricsAddress).StringVar(&cfg.MetricsAddress)
+	app.Flag("expose-debug-endpoints", "When enabled, also serves the desired endpoints and the plan of the most recent synchronization as JSON on /debug/endpoints and /debug/plan of the metrics address; this reveals all managed hostnames (default: disabled)").BoolVar(&cfg.ExposeDebugEndpoints)
 	app.Flag("log-level", "Set the level of logging. (default: info, options: panic, debug, info, warning, error, fatal").Default(defaultConfig.LogLevel).EnumVar(&cfg.LogLevel, allLogLevelsAsStrings()...)
 
 	_, err := app.Parse(args)
@@ -38,9 +38,18 @@ var (
 		ContourLoadBalancerService:  "heptio-contour/contour",
 		GlooNamespace:               "gloo-system",
 		SkipperRouteGroupVersion:    "zalando.org/v1",
+		TraefikService:              "",
+		KongProxyService:            "",
 		Sources:                     []string{"service"},
-		Namespace:                   "",
 		FQDNTemplate:                "",
+		NodeAddressPreference:       "ExternalIP",
+		NodePortLabelFilter:         "",
+		NodeLabelFilter:             "",
+		PodLabelFilter:              "",
+		PreferLBTarget:              "both",
+		PodFQDNTemplate:             "",
+		ServiceImportFQDNTemplate:   "",
+		PodSourceTTL:                time.Minute,
 		Compatibility:               "",
 		Provider:                    "google",
 		GoogleProject:               "",
@@ -105,12 +114,18 @@ var (
 		TXTPrefix:                   "",
 		TXTCacheInterval:            0,
 		Interval:                    time.Minute,
+		IntervalJitter:              0,
+		MaxRecordsStaleness:         0,
 		MinEventSyncInterval:        5 * time.Second,
+		ShutdownGracePeriod:         20 * time.Second,
+		LeaderElectionNamespace:     "default",
+		LeaderElectionLeaseName:     "external-dns",
 		Once:                        false,
 		DryRun:                      false,
 		UpdateEvents:                false,
 		LogFormat:                   "text",
 		MetricsAddress:              ":7979",
+		ExposeDebugEndpoints:        false,
 		LogLevel:                    logrus.InfoLevel.String(),
 		ConnectorSourceServer:       "localhost:8080",
 		ExoscaleEndpoint:            "https://api.exoscale.ch/dns",
@@ -138,11 +153,22 @@ var (
 		ContourLoadBalancerService:  "heptio-contour-other/contour-other",
 		GlooNamespace:               "gloo-not-system",
 		SkipperRouteGroupVersion:    "zalando.org/v2",
+		TraefikService:              "traefik/traefik",
+		KongProxyService:            "kong/kong-proxy",
 		Sources:                     []string{"service", "ingress", "connector"},
-		Namespace:                   "namespace",
+		Namespaces:                  []string{"namespace", "other-namespace"},
 		IgnoreHostnameAnnotation:    true,
 		IgnoreIngressTLSSpec:        true,
 		IgnoreIngressRulesSpec:      true,
+		IngressClassNames:           []string{"nginx", "internal"},
+		NodeAddressPreference:       "InternalIP",
+		NodePortLabelFilter:         "node-role.kubernetes.io/ingress=true",
+		NodeLabelFilter:             "node-role.kubernetes.io/worker",
+		PodLabelFilter:              "app=edge",
+		PreferLBTarget:              "ip",
+		PodFQDNTemplate:             "edge-{{.Spec.NodeName}}.example.com",
+		ServiceImportFQDNTemplate:   "{{.Name}}.{{.Namespace}}.clusterset.example.com",
+		PodSourceTTL:                30 * time.Second,
 		FQDNTemplate:                "{{.Name}}.service.example.com",
 		Compatibility:               "mate",
 		Provider:                    "google",
@@ -158,6 +184,7 @@ var (
 		ZoneIDFilter:                []string{"/hostedzone/ZTST1", "/hostedzone/ZTST2"},
 		TargetNetFilter:             []string{"10.0.0.0/9", "10.1.0.0/9"},
 		ExcludeTargetNets:           []string{"1.0.0.0/9", "1.1.0.0/9"},
+		ResolveCNAMETargetsAtApex:   []string{"example.org", "company.com"},
 		AlibabaCloudConfigFile:      "/etc/kubernetes/alibaba-cloud.json",
 		AWSZoneType:                 "private",
 		AWSZoneTagFilter:            []string{"tag=foo"},
@@ -213,13 +240,21 @@ var (
 		TXTOwnerID:                  "owner-1",
 		TXTPrefix:                   "associated-txt-record",
 		TXTCacheInterval:            12 * time.Hour,
+		TXTFailOnOwnerConflict:      true,
 		Interval:                    10 * time.Minute,
+		IntervalJitter:              0.2,
+		MaxRecordsStaleness:         10 * time.Minute,
 		MinEventSyncInterval:        50 * time.Second,
+		ShutdownGracePeriod:         10 * time.Second,
+		LeaderElection:              true,
+		LeaderElectionNamespace:     "external-dns",
+		LeaderElectionLeaseName:     "external-dns-public",
 		Once:                        true,
 		DryRun:                      true,
 		UpdateEvents:                true,
 		LogFormat:                   "json",
 		MetricsAddress:              "127.0.0.1:9099",
+		ExposeDebugEndpoints:        true,
 		LogLevel:                    logrus.DebugLevel.String(),
 		ConnectorSourceServer:       "localhost:8081",
 		ExoscaleEndpoint:            "https://api.foo.ch/dns",
@@ -268,14 +303,27 @@ func TestParseFlags(t *testing.T) {
 				"--contour-load-balancer=heptio-contour-other/contour-other",
 				"--gloo-namespace=gloo-not-system",
 				"--skipper-routegroup-groupversion=zalando.org/v2",
+				"--traefik-service=traefik/traefik",
+				"--kong-proxy-service=kong/kong-proxy",
 				"--source=service",
 				"--source=ingress",
 				"--source=connector",
 				"--namespace=namespace",
+				"--namespace=other-namespace",
 				"--fqdn-template={{.Name}}.service.example.com",
 				"--ignore-hostname-annotation",
 				"--ignore-ingress-tls-spec",
 				"--ignore-ingress-rules-spec",
+				"--ingress-class=nginx",
+				"--ingress-class=internal",
+				"--node-address-preference=InternalIP",
+				"--node-port-label-filter=node-role.kubernetes.io/ingress=true",
+				"--node-label-filter=node-role.kubernetes.io/worker",
+				"--pod-label-filter=app=edge",
+				"--prefer-lb-target=ip",
+				"--pod-fqdn-template=edge-{{.Spec.NodeName}}.example.com",
+				"--serviceimport-fqdn-template={{.Name}}.{{.Namespace}}.clusterset.example.com",
+				"--pod-source-ttl=30s",
 				"--compatibility=mate",
 				"--provider=google",
 				"--google-project=project",
@@ -335,6 +383,8 @@ func TestParseFlags(t *testing.T) {
 				"--target-net-filter=10.1.0.0/9",
 				"--exclude-target-net=1.0.0.0/9",
 				"--exclude-target-net=1.1.0.0/9",
+				"--resolve-cname-targets-at-apex=example.org",
+				"--resolve-cname-targets-at-apex=company.com",
 				"--aws-zone-type=private",
 				"--aws-zone-tags=tag=foo",
 				"--aws-assume-role=some-other-role",
@@ -351,13 +401,21 @@ func TestParseFlags(t *testing.T) {
 				"--txt-owner-id=owner-1",
 				"--txt-prefix=associated-txt-record",
 				"--txt-cache-interval=12h",
+				"--txt-fail-on-owner-conflict",
 				"--interval=10m",
+				"--interval-jitter=0.2",
+				"--max-records-staleness=10m",
 				"--min-event-sync-interval=50s",
+				"--shutdown-grace-period=10s",
+				"--leader-election",
+				"--leader-election-namespace=external-dns",
+				"--leader-election-lease-name=external-dns-public",
 				"--once",
 				"--dry-run",
 				"--events",
 				"--log-format=json",
 				"--metrics-address=127.0.0.1:9099",
+				"--expose-debug-endpoints",
 				"--log-level=debug",
 				"--connector-source-server=localhost:8081",
 				"--exoscale-endpoint=https://api.foo.ch/dns",
@@ -393,12 +451,23 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_CONTOUR_LOAD_BALANCER":           "heptio-contour-other/contour-other",
 				"EXTERNAL_DNS_GLOO_NAMESPACE":                  "gloo-not-system",
 				"EXTERNAL_DNS_SKIPPER_ROUTEGROUP_GROUPVERSION": "zalando.org/v2",
+				"EXTERNAL_DNS_TRAEFIK_SERVICE":                 "traefik/traefik",
+				"EXTERNAL_DNS_KONG_PROXY_SERVICE":              "kong/kong-proxy",
 				"EXTERNAL_DNS_SOURCE":                          "service\ningress\nconnector",
-				"EXTERNAL_DNS_NAMESPACE":                       "namespace",
+				"EXTERNAL_DNS_NAMESPACE":                       "namespace\nother-namespace",
 				"EXTERNAL_DNS_FQDN_TEMPLATE":                   "{{.Name}}.service.example.com",
 				"EXTERNAL_DNS_IGNORE_HOSTNAME_ANNOTATION":      "1",
 				"EXTERNAL_DNS_IGNORE_INGRESS_TLS_SPEC":         "1",
 				"EXTERNAL_DNS_IGNORE_INGRESS_RULES_SPEC":       "1",
+				"EXTERNAL_DNS_INGRESS_CLASS":                   "nginx\ninternal",
+				"EXTERNAL_DNS_NODE_ADDRESS_PREFERENCE":         "InternalIP",
+				"EXTERNAL_DNS_NODE_PORT_LABEL_FILTER":          "node-role.kubernetes.io/ingress=true",
+				"EXTERNAL_DNS_NODE_LABEL_FILTER":               "node-role.kubernetes.io/worker",
+				"EXTERNAL_DNS_POD_LABEL_FILTER":                "app=edge",
+				"EXTERNAL_DNS_PREFER_LB_TARGET":                "ip",
+				"EXTERNAL_DNS_POD_FQDN_TEMPLATE":               "edge-{{.Spec.NodeName}}.example.com",
+				"EXTERNAL_DNS_SERVICEIMPORT_FQDN_TEMPLATE":     "{{.Name}}.{{.Namespace}}.clusterset.example.com",
+				"EXTERNAL_DNS_POD_SOURCE_TTL":                  "30s",
 				"EXTERNAL_DNS_COMPATIBILITY":                   "mate",
 				"EXTERNAL_DNS_PROVIDER":                        "google",
 				"EXTERNAL_DNS_GOOGLE_PROJECT":                  "project",
@@ -443,6 +512,7 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_REGEX_DOMAIN_EXCLUSION":          "xapi\\.(example\\.org|company\\.com)$",
 				"EXTERNAL_DNS_TARGET_NET_FILTER":               "10.0.0.0/9\n10.1.0.0/9",
 				"EXTERNAL_DNS_EXCLUDE_TARGET_NET":              "1.0.0.0/9\n1.1.0.0/9",
+				"EXTERNAL_DNS_RESOLVE_CNAME_TARGETS_AT_APEX":   "example.org\ncompany.com",
 				"EXTERNAL_DNS_PDNS_SERVER":                     "http://ns.example.com:8081",
 				"EXTERNAL_DNS_PDNS_API_KEY":                    "some-secret-key",
 				"EXTERNAL_DNS_PDNS_TLS_ENABLED":                "1",
@@ -468,13 +538,21 @@ func TestParseFlags(t *testing.T) {
 				"EXTERNAL_DNS_TXT_OWNER_ID":                    "owner-1",
 				"EXTERNAL_DNS_TXT_PREFIX":                      "associated-txt-record",
 				"EXTERNAL_DNS_TXT_CACHE_INTERVAL":              "12h",
+				"EXTERNAL_DNS_TXT_FAIL_ON_OWNER_CONFLICT":      "1",
 				"EXTERNAL_DNS_INTERVAL":                        "10m",
+				"EXTERNAL_DNS_INTERVAL_JITTER":                 "0.2",
+				"EXTERNAL_DNS_MAX_RECORDS_STALENESS":           "10m",
 				"EXTERNAL_DNS_MIN_EVENT_SYNC_INTERVAL":         "50s",
+				"EXTERNAL_DNS_SHUTDOWN_GRACE_PERIOD":           "10s",
+				"EXTERNAL_DNS_LEADER_ELECTION":                 "1",
+				"EXTERNAL_DNS_LEADER_ELECTION_NAMESPACE":       "external-dns",
+				"EXTERNAL_DNS_LEADER_ELECTION_LEASE_NAME":      "external-dns-public",
 				"EXTERNAL_DNS_ONCE":                            "1",
 				"EXTERNAL_DNS_DRY_RUN":                         "1",
 				"EXTERNAL_DNS_EVENTS":                          "1",
 				"EXTERNAL_DNS_LOG_FORMAT":                      "json",
 				"EXTERNAL_DNS_METRICS_ADDRESS":                 "127.0.0.1:9099",
+				"EXTERNAL_DNS_EXPOSE_DEBUG_ENDPOINTS":          "1",
 				"EXTERNAL_DNS_LOG_LEVEL":                       "debug",
 				"EXTERNAL_DNS_CONNECTOR_SOURCE_SERVER":         "localhost:8081",
 				"EXTERNAL_DNS_EXOSCALE_ENDPOINT":               "https://api.foo.ch/dns",
@@ -112,9 +112,28 @@ func ValidateConfig(cfg *externaldns.Config) error {
 		return errors.New("txt-prefix and txt-suffix are mutual exclusive")
 	}
 
+	if cfg.IntervalJitter < 0 || cfg.IntervalJitter >= 1 {
+		return errors.New("--interval-jitter must be at least 0 and less than 1")
+	}
+
 	_, err := labels.Parse(cfg.LabelFilter)
 	if err != nil {
 		return errors.New("--label-filter does not specify a valid label selector")
 	}
+
+	_, err = labels.Parse(cfg.NodePortLabelFilter)
+	if err != nil {
+		return errors.New("--node-port-label-filter does not specify a valid label selector")
+	}
+
+	_, err = labels.Parse(cfg.NodeLabelFilter)
+	if err != nil {
+		return errors.New("--node-label-filter does not specify a valid label selector")
+	}
+
+	_, err = labels.Parse(cfg.PodLabelFilter)
+	if err != nil {
+		return errors.New("--pod-label-filter does not specify a valid label selector")
+	}
 	return nil
 }
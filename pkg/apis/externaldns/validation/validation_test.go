@@ -153,6 +153,19 @@ func TestValidateBadRfc2136Batch(t *testing.T) {
 	assert.NotNil(t, err)
 }
 
+func TestValidateBadIntervalJitter(t *testing.T) {
+	for _, jitter := range []float64{-0.1, 1, 1.5} {
+		cfg := externaldns.NewConfig()
+
+		cfg.LogFormat = "json"
+		cfg.Sources = []string{"test-source"}
+		cfg.Provider = "test-provider"
+		cfg.IntervalJitter = jitter
+
+		assert.Error(t, ValidateConfig(cfg), "jitter %v", jitter)
+	}
+}
+
 func TestValidateGoodRfc2136Config(t *testing.T) {
 	cfg := externaldns.NewConfig()
 
@@ -0,0 +1,75 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"encoding/json"
+	"io"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// ChangesDocumentVersion is the version of the ChangesDocument format. It must be
+// bumped whenever a field is removed or its meaning changes.
+const ChangesDocumentVersion = "v1"
+
+// ChangesDocument is the machine-readable representation of a set of Changes
+// meant to be consumed by external tooling, e.g. for change review.
+type ChangesDocument struct {
+	// Version of the document format
+	Version string `json:"version"`
+	// Records that need to be created
+	Create []*endpoint.Endpoint `json:"create"`
+	// Records that need to be updated
+	Update []UpdateDocument `json:"update"`
+	// Records that need to be deleted
+	Delete []*endpoint.Endpoint `json:"delete"`
+}
+
+// UpdateDocument pairs the current and the desired state of an updated record.
+type UpdateDocument struct {
+	Old *endpoint.Endpoint `json:"old"`
+	New *endpoint.Endpoint `json:"new"`
+}
+
+// NewChangesDocument converts the given changes into a ChangesDocument.
+func NewChangesDocument(changes *Changes) *ChangesDocument {
+	doc := &ChangesDocument{
+		Version: ChangesDocumentVersion,
+		Create:  []*endpoint.Endpoint{},
+		Update:  []UpdateDocument{},
+		Delete:  []*endpoint.Endpoint{},
+	}
+	if changes == nil {
+		return doc
+	}
+	doc.Create = append(doc.Create, changes.Create...)
+	doc.Delete = append(doc.Delete, changes.Delete...)
+	for i := range changes.UpdateNew {
+		update := UpdateDocument{New: changes.UpdateNew[i]}
+		if i < len(changes.UpdateOld) {
+			update.Old = changes.UpdateOld[i]
+		}
+		doc.Update = append(doc.Update, update)
+	}
+	return doc
+}
+
+// WriteChanges writes the given changes to w as a single line of JSON.
+func WriteChanges(w io.Writer, changes *Changes) error {
+	return json.NewEncoder(w).Encode(NewChangesDocument(changes))
+}
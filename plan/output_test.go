@@ -0,0 +1,53 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestWriteChanges(t *testing.T) {
+	changes := &Changes{
+		Create:    []*endpoint.Endpoint{endpoint.NewEndpoint("create.example.com", endpoint.RecordTypeA, "1.2.3.4")},
+		UpdateOld: []*endpoint.Endpoint{endpoint.NewEndpoint("update.example.com", endpoint.RecordTypeA, "1.1.1.1")},
+		UpdateNew: []*endpoint.Endpoint{endpoint.NewEndpoint("update.example.com", endpoint.RecordTypeA, "2.2.2.2")},
+		Delete:    []*endpoint.Endpoint{endpoint.NewEndpoint("delete.example.com", endpoint.RecordTypeCNAME, "foo.example.com")},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, WriteChanges(&buf, changes))
+
+	expected := `{"version":"v1",` +
+		`"create":[{"dnsName":"create.example.com","targets":["1.2.3.4"],"recordType":"A"}],` +
+		`"update":[{"old":{"dnsName":"update.example.com","targets":["1.1.1.1"],"recordType":"A"},"new":{"dnsName":"update.example.com","targets":["2.2.2.2"],"recordType":"A"}}],` +
+		`"delete":[{"dnsName":"delete.example.com","targets":["foo.example.com"],"recordType":"CNAME"}]}` + "\n"
+	assert.Equal(t, expected, buf.String())
+}
+
+func TestWriteChangesEmpty(t *testing.T) {
+	for _, changes := range []*Changes{nil, {}} {
+		var buf bytes.Buffer
+		require.NoError(t, WriteChanges(&buf, changes))
+		assert.Equal(t, `{"version":"v1","create":[],"update":[],"delete":[]}`+"\n", buf.String())
+	}
+}
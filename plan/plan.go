@@ -198,7 +198,7 @@ func inheritOwner(from, to *endpoint.Endpoint) {
 }
 
 func targetChanged(desired, current *endpoint.Endpoint) bool {
-	return !desired.Targets.Same(current.Targets)
+	return !desired.Targets.SameForType(current.Targets, desired.RecordType)
 }
 
 func shouldUpdateTTL(desired, current *endpoint.Endpoint) bool {
@@ -0,0 +1,47 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// TargetChanges computes the per-target difference of an update, allowing providers
+// which store every target as an individual record to only touch the targets that
+// actually changed. Targets are compared in their normalized form, see
+// endpoint.NormalizeTarget.
+func TargetChanges(old, new *endpoint.Endpoint) (added, removed endpoint.Targets) {
+	added = targetsMissingFrom(new.Targets, old.Targets, old.RecordType)
+	removed = targetsMissingFrom(old.Targets, new.Targets, old.RecordType)
+	return added, removed
+}
+
+// targetsMissingFrom returns the targets of t which are not part of o, both being targets
+// of the given record type.
+func targetsMissingFrom(t, o endpoint.Targets, recordType string) endpoint.Targets {
+	existing := make(map[string]struct{}, len(o))
+	for _, target := range o {
+		existing[endpoint.NormalizeTarget(recordType, target)] = struct{}{}
+	}
+	missing := endpoint.Targets{}
+	for _, target := range t {
+		if _, ok := existing[endpoint.NormalizeTarget(recordType, target)]; !ok {
+			missing = append(missing, target)
+		}
+	}
+	return missing
+}
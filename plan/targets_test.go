@@ -0,0 +1,86 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestTargetChanges(t *testing.T) {
+	for _, tc := range []struct {
+		title           string
+		recordType      string
+		old             endpoint.Targets
+		new             endpoint.Targets
+		expectedAdded   endpoint.Targets
+		expectedRemoved endpoint.Targets
+	}{
+		{
+			title:           "add a single target",
+			recordType:      endpoint.RecordTypeA,
+			old:             endpoint.Targets{"1.1.1.1", "1.1.1.2", "1.1.1.3", "1.1.1.4", "1.1.1.5"},
+			new:             endpoint.Targets{"1.1.1.1", "1.1.1.2", "1.1.1.3", "1.1.1.4", "1.1.1.5", "1.1.1.6"},
+			expectedAdded:   endpoint.Targets{"1.1.1.6"},
+			expectedRemoved: endpoint.Targets{},
+		},
+		{
+			title:           "remove a single target",
+			recordType:      endpoint.RecordTypeA,
+			old:             endpoint.Targets{"1.1.1.1", "1.1.1.2", "1.1.1.3"},
+			new:             endpoint.Targets{"1.1.1.1", "1.1.1.3"},
+			expectedAdded:   endpoint.Targets{},
+			expectedRemoved: endpoint.Targets{"1.1.1.2"},
+		},
+		{
+			title:           "swap a single target",
+			recordType:      endpoint.RecordTypeA,
+			old:             endpoint.Targets{"1.1.1.1", "1.1.1.2", "1.1.1.3"},
+			new:             endpoint.Targets{"1.1.1.1", "1.1.1.4", "1.1.1.3"},
+			expectedAdded:   endpoint.Targets{"1.1.1.4"},
+			expectedRemoved: endpoint.Targets{"1.1.1.2"},
+		},
+		{
+			title:           "targets differing in case only",
+			recordType:      endpoint.RecordTypeCNAME,
+			old:             endpoint.Targets{"LB.example.com"},
+			new:             endpoint.Targets{"lb.example.com"},
+			expectedAdded:   endpoint.Targets{},
+			expectedRemoved: endpoint.Targets{},
+		},
+		{
+			title:           "TXT targets differing in case only",
+			recordType:      endpoint.RecordTypeTXT,
+			old:             endpoint.Targets{"Some Text"},
+			new:             endpoint.Targets{"some text"},
+			expectedAdded:   endpoint.Targets{"some text"},
+			expectedRemoved: endpoint.Targets{"Some Text"},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			added, removed := TargetChanges(
+				endpoint.NewEndpoint("foo.example.com", tc.recordType, tc.old...),
+				endpoint.NewEndpoint("foo.example.com", tc.recordType, tc.new...),
+			)
+			assert.Equal(t, tc.expectedAdded, added)
+			assert.Equal(t, tc.expectedRemoved, removed)
+		})
+	}
+}
@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/digitalocean/godo"
@@ -130,9 +131,17 @@ func mergeEndpointsByNameType(endpoints []*endpoint.Endpoint) []*endpoint.Endpoi
 		return endpoints
 	}
 
-	// Otherwise, construct a new list of endpoints with the endpoints merged.
+	// Otherwise, construct a new list of endpoints with the endpoints merged. Both the endpoints
+	// and their targets are sorted, so that the result doesn't depend on the order of the records.
+	keys := make([]string, 0, len(endpointsByNameType))
+	for key := range endpointsByNameType {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
 	var result []*endpoint.Endpoint
-	for _, endpoints := range endpointsByNameType {
+	for _, key := range keys {
+		endpoints := endpointsByNameType[key]
 		dnsName := endpoints[0].DNSName
 		recordType := endpoints[0].RecordType
 
@@ -140,6 +149,7 @@ func mergeEndpointsByNameType(endpoints []*endpoint.Endpoint) []*endpoint.Endpoi
 		for i, e := range endpoints {
 			targets[i] = e.Targets[0]
 		}
+		sort.Strings(targets)
 
 		e := endpoint.NewEndpoint(dnsName, recordType, targets...)
 		result = append(result, e)
@@ -693,3 +693,25 @@ func TestDigitalOceanMergeRecordsByNameType(t *testing.T) {
 	assert.Equal(t, 1, len(merged[2].Targets))
 	assert.Equal(t, "somewhere.out.there.com", merged[2].Targets[0])
 }
+
+func TestDigitalOceanMergeRecordsByNameTypeIsDeterministic(t *testing.T) {
+	xs := []*endpoint.Endpoint{
+		endpoint.NewEndpoint("foo.example.com", "A", "5.6.7.8"),
+		endpoint.NewEndpoint("bar.example.com", "A", "1.2.3.4"),
+		endpoint.NewEndpoint("foo.example.com", "A", "1.2.3.4"),
+		endpoint.NewEndpoint("foo.example.com", "A", "3.4.5.6"),
+	}
+	reversed := make([]*endpoint.Endpoint, 0, len(xs))
+	for i := len(xs) - 1; i >= 0; i-- {
+		reversed = append(reversed, xs[i])
+	}
+
+	merged := mergeEndpointsByNameType(xs)
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, merged, mergeEndpointsByNameType(xs))
+		assert.Equal(t, merged, mergeEndpointsByNameType(reversed))
+	}
+	require.Len(t, merged, 2)
+	assert.Equal(t, "bar.example.com", merged[0].DNSName)
+	assert.Equal(t, endpoint.Targets{"1.2.3.4", "3.4.5.6", "5.6.7.8"}, merged[1].Targets)
+}
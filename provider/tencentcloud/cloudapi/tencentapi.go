@@ -258,16 +258,16 @@ func dealWithError(action Action, request string, err error) bool {
 }
 
 func APIErrorRecord(apiAction Action, request string, response string, err error) {
-	log.Infof(fmt.Sprintf("APIError API: %s/%s Request: %s, Response: %s, Error: %s", apiAction.Service, apiAction.Name, request, response, err.Error()))
+	log.Infof("APIError API: %s/%s Request: %s, Response: %s, Error: %s", apiAction.Service, apiAction.Name, request, response, err.Error())
 }
 
 func APIRecord(apiAction Action, request string, response string) {
 	message := fmt.Sprintf("APIRecord API: %s/%s Request: %s, Response: %s", apiAction.Service, apiAction.Name, request, response)
 
 	if apiAction.ReadOnly {
-		// log.Infof(message)
+		// log.Info(message)
 	} else {
-		log.Infof(message)
+		log.Info(message)
 	}
 }
 
@@ -17,11 +17,13 @@ limitations under the License.
 package vinyldns
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"os"
 	"testing"
 
+	log "github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/vinyldns/go-vinyldns/vinyldns"
@@ -223,3 +225,34 @@ func (m *mockVinyldnsZoneInterface) RecordSetDelete(zoneID, recordSetID string)
 
 	return r0, args.Error(1)
 }
+
+func TestVinylDNSRecordsLogsNamesVerbatim(t *testing.T) {
+	var buf bytes.Buffer
+	log.SetOutput(&buf)
+	log.SetLevel(log.DebugLevel)
+	defer func() {
+		log.SetOutput(os.Stderr)
+		log.SetLevel(log.InfoLevel)
+	}()
+
+	zone := vinyldns.Zone{ID: "0", Name: "100%.example.com."}
+	record := vinyldns.RecordSet{
+		ZoneID:  "0",
+		Name:    "50%off",
+		TTL:     300,
+		Type:    "A",
+		Records: []vinyldns.Record{{Address: "1.2.3.4"}},
+	}
+
+	client := &mockVinyldnsZoneInterface{}
+	client.On("Zones").Return([]vinyldns.Zone{zone}, nil)
+	client.On("RecordSets", "0").Return([]vinyldns.RecordSet{record}, nil)
+
+	p := vinyldnsProvider{client: client}
+	_, err := p.Records(context.Background())
+	assert.NoError(t, err)
+
+	assert.Contains(t, buf.String(), "Zone: [0:100%.example.com.]")
+	assert.Contains(t, buf.String(), "50%off.A.1.100%.example.com.")
+	assert.NotContains(t, buf.String(), "%!")
+}
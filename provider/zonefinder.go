@@ -24,9 +24,13 @@ func (z ZoneIDName) Add(zoneID, zoneName string) {
 	z[zoneID] = zoneName
 }
 
+// FindZone returns the most specific zone the hostname belongs to. DNS names are compared
+// case-insensitively, the zone name is returned as it was added.
 func (z ZoneIDName) FindZone(hostname string) (suitableZoneID, suitableZoneName string) {
+	hostname = strings.ToLower(hostname)
 	for zoneID, zoneName := range z {
-		if hostname == zoneName || strings.HasSuffix(hostname, "."+zoneName) {
+		name := strings.ToLower(zoneName)
+		if hostname == name || strings.HasSuffix(hostname, "."+name) {
 			if suitableZoneName == "" || len(zoneName) > len(suitableZoneName) {
 				suitableZoneID = zoneID
 				suitableZoneName = zoneName
@@ -63,3 +63,25 @@ func TestZoneIDName(t *testing.T) {
 	assert.Equal(t, "foo.qux.baz", zoneName)
 	assert.Equal(t, "654321", zoneID)
 }
+
+func TestZoneIDNameCaseInsensitive(t *testing.T) {
+	z := ZoneIDName{}
+	z.Add("123456", "Example.COM")
+	z.Add("654321", "sub.example.com")
+
+	zoneID, zoneName := z.FindZone("name.example.com")
+	assert.Equal(t, "Example.COM", zoneName)
+	assert.Equal(t, "123456", zoneID)
+
+	zoneID, zoneName = z.FindZone("Name.EXAMPLE.com")
+	assert.Equal(t, "Example.COM", zoneName)
+	assert.Equal(t, "123456", zoneID)
+
+	zoneID, zoneName = z.FindZone("EXAMPLE.COM")
+	assert.Equal(t, "Example.COM", zoneName)
+	assert.Equal(t, "123456", zoneID)
+
+	zoneID, zoneName = z.FindZone("name.Sub.Example.com")
+	assert.Equal(t, "sub.example.com", zoneName)
+	assert.Equal(t, "654321", zoneID)
+}
@@ -50,12 +50,15 @@ type TXTRegistry struct {
 
 	managedRecordTypes []string
 
+	// failOnOwnerConflict makes Records fail if a record is claimed by this instance and another owner
+	failOnOwnerConflict bool
+
 	// missingTXTRecords stores TXT records which are missing after the migration to the new format
 	missingTXTRecords []*endpoint.Endpoint
 }
 
 // NewTXTRegistry returns new TXTRegistry object
-func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID string, cacheInterval time.Duration, txtWildcardReplacement string, managedRecordTypes []string) (*TXTRegistry, error) {
+func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID string, cacheInterval time.Duration, txtWildcardReplacement string, managedRecordTypes []string, failOnOwnerConflict bool) (*TXTRegistry, error) {
 	if ownerID == "" {
 		return nil, errors.New("owner id cannot be empty")
 	}
@@ -73,11 +76,12 @@ func NewTXTRegistry(provider provider.Provider, txtPrefix, txtSuffix, ownerID st
 		cacheInterval:       cacheInterval,
 		wildcardReplacement: txtWildcardReplacement,
 		managedRecordTypes:  managedRecordTypes,
+		failOnOwnerConflict: failOnOwnerConflict,
 	}, nil
 }
 
 func getSupportedTypes() []string {
-	return []string{endpoint.RecordTypeA, endpoint.RecordTypeCNAME, endpoint.RecordTypeNS}
+	return []string{endpoint.RecordTypeA, endpoint.RecordTypeAAAA, endpoint.RecordTypeCNAME, endpoint.RecordTypeNS}
 }
 
 func (im *TXTRegistry) GetDomainFilter() endpoint.DomainFilterInterface {
@@ -123,7 +127,11 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 		if err != nil {
 			return nil, err
 		}
-		key := fmt.Sprintf("%s::%s", im.mapper.toEndpointName(record.DNSName), record.SetIdentifier)
+		name, recordType := im.mapper.toEndpointName(record.DNSName)
+		key := fmt.Sprintf("%s::%s::%s", name, recordType, record.SetIdentifier)
+		if err := im.checkOwnerConflict(name, record, labelMap[key], labels); err != nil {
+			return nil, err
+		}
 		labelMap[key] = labels
 		txtRecordsMap[record.DNSName] = struct{}{}
 	}
@@ -138,8 +146,13 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 			dnsNameSplit[0] = im.wildcardReplacement
 		}
 		dnsName := strings.Join(dnsNameSplit, ".")
-		key := fmt.Sprintf("%s::%s", dnsName, ep.SetIdentifier)
-		if labels, ok := labelMap[key]; ok {
+		key := fmt.Sprintf("%s::%s::%s", dnsName, ep.RecordType, ep.SetIdentifier)
+		labels, ok := labelMap[key]
+		if !ok {
+			// Fall back to the TXT record of the old format, which has no record type.
+			labels, ok = labelMap[fmt.Sprintf("%s::%s::%s", dnsName, "", ep.SetIdentifier)]
+		}
+		if ok {
 			for k, v := range labels {
 				ep.Labels[k] = v
 			}
@@ -177,6 +190,43 @@ func (im *TXTRegistry) Records(ctx context.Context) ([]*endpoint.Endpoint, error
 	return endpoints, nil
 }
 
+// checkOwnerConflict warns about a TXT record claiming another owner than the previous TXT record of
+// the same name and record type, or than its other targets, as two instances with different owner ids then manage the
+// same records. If this instance is one of the owners and failOnOwnerConflict is set, an error is returned.
+func (im *TXTRegistry) checkOwnerConflict(name string, record *endpoint.Endpoint, previous endpoint.Labels, labels endpoint.Labels) error {
+	owners := []string{labels[endpoint.OwnerLabelKey]}
+	if previous != nil {
+		owners = append(owners, previous[endpoint.OwnerLabelKey])
+	}
+	for _, target := range record.Targets[1:] {
+		if other, err := endpoint.NewLabelsFromString(target); err == nil {
+			owners = append(owners, other[endpoint.OwnerLabelKey])
+		}
+	}
+
+	conflict, involved := false, false
+	for _, owner := range owners {
+		if owner != owners[0] {
+			conflict = true
+		}
+		if owner == im.ownerID {
+			involved = true
+		}
+	}
+	if !conflict {
+		return nil
+	}
+	if !involved {
+		log.Debugf("Record %s is claimed by the owners %q", name, owners)
+		return nil
+	}
+	if im.failOnOwnerConflict {
+		return fmt.Errorf("record %s is claimed by the owners %q, every ExternalDNS instance must have its own owner id and manage distinct records", name, owners)
+	}
+	log.Warnf("Record %s is claimed by the owners %q, every ExternalDNS instance must have its own owner id and manage distinct records", name, owners)
+	return nil
+}
+
 // MissingRecords returns the TXT record to be created.
 // The missing records are collected during the run of Records method.
 func (im *TXTRegistry) MissingRecords() []*endpoint.Endpoint {
@@ -191,19 +241,23 @@ func (im *TXTRegistry) generateTXTRecord(r *endpoint.Endpoint) []*endpoint.Endpo
 	if r.RecordType == endpoint.RecordTypeTXT {
 		return nil
 	}
-	// old TXT record format
-	txt := endpoint.NewEndpoint(im.mapper.toTXTName(r.DNSName), endpoint.RecordTypeTXT, r.Labels.Serialize(true)).WithSetIdentifier(r.SetIdentifier)
-	txt.ProviderSpecific = r.ProviderSpecific
+	endpoints := []*endpoint.Endpoint{}
+	// old TXT record format, it has no record type, so the one of an AAAA record would be the one
+	// of the A record with the same name. AAAA records only get the new format.
+	if r.RecordType != endpoint.RecordTypeAAAA {
+		txt := endpoint.NewEndpoint(im.mapper.toTXTName(r.DNSName), endpoint.RecordTypeTXT, r.Labels.Serialize(true)).WithSetIdentifier(r.SetIdentifier)
+		txt.ProviderSpecific = r.ProviderSpecific
+		endpoints = append(endpoints, txt)
+	}
 	// new TXT record format (containing record type)
 	txtNew := endpoint.NewEndpoint(im.mapper.toNewTXTName(r.DNSName, r.RecordType), endpoint.RecordTypeTXT, r.Labels.Serialize(true))
 	if txtNew != nil {
 		txtNew.WithSetIdentifier(r.SetIdentifier)
 		txtNew.ProviderSpecific = r.ProviderSpecific
-	} else {
-		return []*endpoint.Endpoint{txt}
+		endpoints = append(endpoints, txtNew)
 	}
 
-	return []*endpoint.Endpoint{txt, txtNew}
+	return endpoints
 }
 
 // ApplyChanges updates dns provider with the changes
@@ -286,7 +340,7 @@ func (im *TXTRegistry) AdjustEndpoints(endpoints []*endpoint.Endpoint) []*endpoi
 */
 
 type nameMapper interface {
-	toEndpointName(string) string
+	toEndpointName(string) (string, string)
 	toTXTName(string) string
 	toNewTXTName(string, string) string
 }
@@ -353,18 +407,49 @@ func (pr affixNameMapper) isSuffix() bool {
 	return len(pr.prefix) == 0 && len(pr.suffix) > 0
 }
 
-func (pr affixNameMapper) toEndpointName(txtDNSName string) string {
+// toEndpointName returns the name of the endpoint a TXT record manages and the record type
+// the TXT record name carries, which is empty for TXT records of the old format.
+func (pr affixNameMapper) toEndpointName(txtDNSName string) (string, string) {
+	recordType := pr.extractRecordType(strings.ToLower(txtDNSName))
 	lowerDNSName := dropRecordType(strings.ToLower(txtDNSName))
 
 	// drop prefix
 	if strings.HasPrefix(lowerDNSName, pr.prefix) && pr.isPrefix() {
-		return pr.dropAffix(lowerDNSName)
+		return pr.dropAffix(lowerDNSName), recordType
 	}
 
 	// drop suffix
 	if pr.isSuffix() {
 		DNSName := strings.SplitN(lowerDNSName, ".", 2)
-		return pr.dropAffix(DNSName[0]) + "." + DNSName[1]
+		return pr.dropAffix(DNSName[0]) + "." + DNSName[1], recordType
+	}
+	return "", recordType
+}
+
+// extractRecordType returns the record type of a TXT record name, found either in the templated
+// affix or in front of the endpoint name, or an empty string for TXT records of the old format.
+func (pr affixNameMapper) extractRecordType(txtDNSName string) string {
+	if pr.recordTypeInAffix() {
+		for _, t := range getSupportedTypes() {
+			iPrefix := strings.ReplaceAll(pr.prefix, recordTemplate, strings.ToLower(t))
+			iSuffix := strings.ReplaceAll(pr.suffix, recordTemplate, strings.ToLower(t))
+			if pr.isPrefix() && strings.HasPrefix(txtDNSName, iPrefix) {
+				return t
+			}
+			if pr.isSuffix() && strings.HasSuffix(strings.SplitN(txtDNSName, ".", 2)[0], iSuffix) {
+				return t
+			}
+		}
+		return ""
+	}
+	name := txtDNSName
+	if pr.isPrefix() {
+		name = strings.TrimPrefix(name, pr.prefix)
+	}
+	for _, t := range getSupportedTypes() {
+		if strings.HasPrefix(name, strings.ToLower(t)+"-") {
+			return t
+		}
 	}
 	return ""
 }
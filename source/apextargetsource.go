@@ -0,0 +1,148 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// apexTargetSource is a Source that resolves the hostname targets of CNAME endpoints at a zone
+// apex to A and AAAA endpoints, as a CNAME record isn't allowed at the apex of a zone.
+type apexTargetSource struct {
+	source   Source
+	apexes   map[string]struct{}
+	lookupIP func(ctx context.Context, host string) ([]net.IP, error)
+	// resolved holds the addresses of the last successful lookup of each hostname target.
+	resolved map[string][]net.IP
+}
+
+// NewApexTargetSource creates a new apexTargetSource wrapping the provided Source. The hostname
+// targets of CNAME endpoints named like one of the given zone apexes are looked up on each call
+// of Endpoints.
+func NewApexTargetSource(source Source, apexes []string) Source {
+	return newApexTargetSource(source, apexes, func(ctx context.Context, host string) ([]net.IP, error) {
+		return net.DefaultResolver.LookupIP(ctx, "ip", host)
+	})
+}
+
+func newApexTargetSource(source Source, apexes []string, lookupIP func(ctx context.Context, host string) ([]net.IP, error)) *apexTargetSource {
+	apexSet := make(map[string]struct{}, len(apexes))
+	for _, apex := range apexes {
+		apexSet[normalizeApex(apex)] = struct{}{}
+	}
+	return &apexTargetSource{
+		source:   source,
+		apexes:   apexSet,
+		lookupIP: lookupIP,
+		resolved: map[string][]net.IP{},
+	}
+}
+
+// Endpoints collects endpoints from its wrapped source and replaces the CNAME endpoints at a zone
+// apex with A and AAAA endpoints pointing to the addresses of their targets. If a target can't be
+// resolved, the addresses of its last successful lookup are used. If there are none, e.g. after a
+// restart, an error is returned, as dropping the endpoint would delete the existing apex records.
+func (as *apexTargetSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	endpoints, err := as.source.Endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if _, ok := as.apexes[normalizeApex(ep.DNSName)]; !ok || ep.RecordType != endpoint.RecordTypeCNAME {
+			result = append(result, ep)
+			continue
+		}
+
+		var ipv4, ipv6 endpoint.Targets
+		for _, target := range ep.Targets {
+			ips, err := as.resolve(ctx, target)
+			if err != nil {
+				return nil, fmt.Errorf("failed to resolve the targets of zone apex %s: %w", ep.DNSName, err)
+			}
+			for _, ip := range ips {
+				if ip.To4() != nil {
+					ipv4 = append(ipv4, ip.String())
+				} else {
+					ipv6 = append(ipv6, ip.String())
+				}
+			}
+		}
+
+		log.Debugf("Resolved CNAME targets %v of zone apex %s to %v and %v", ep.Targets, ep.DNSName, ipv4, ipv6)
+		if len(ipv4) > 0 {
+			result = append(result, apexAddressEndpoint(ep, endpoint.RecordTypeA, ipv4))
+		}
+		if len(ipv6) > 0 {
+			result = append(result, apexAddressEndpoint(ep, endpoint.RecordTypeAAAA, ipv6))
+		}
+	}
+
+	return result, nil
+}
+
+// resolve looks up the addresses of target, falling back to the ones of its last successful lookup.
+func (as *apexTargetSource) resolve(ctx context.Context, target string) ([]net.IP, error) {
+	ips, err := as.lookupIP(ctx, target)
+	if err == nil && len(ips) > 0 {
+		as.resolved[target] = ips
+		return ips, nil
+	}
+	if previous, ok := as.resolved[target]; ok {
+		log.Warnf("Failed to resolve %s, using its previous addresses: %v", target, err)
+		return previous, nil
+	}
+	if err == nil {
+		err = fmt.Errorf("no addresses found")
+	}
+	return nil, fmt.Errorf("failed to resolve %s: %w", target, err)
+}
+
+// apexAddressEndpoint returns a copy of ep with the given record type and targets.
+func apexAddressEndpoint(ep *endpoint.Endpoint, recordType string, targets endpoint.Targets) *endpoint.Endpoint {
+	sort.Strings(targets)
+	labels := endpoint.NewLabels()
+	for k, v := range ep.Labels {
+		labels[k] = v
+	}
+	return &endpoint.Endpoint{
+		DNSName:          ep.DNSName,
+		Targets:          targets,
+		RecordType:       recordType,
+		SetIdentifier:    ep.SetIdentifier,
+		RecordTTL:        ep.RecordTTL,
+		Labels:           labels,
+		ProviderSpecific: ep.ProviderSpecific,
+	}
+}
+
+func normalizeApex(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func (as *apexTargetSource) AddEventHandler(ctx context.Context, handler func()) {
+	as.source.AddEventHandler(ctx, handler)
+}
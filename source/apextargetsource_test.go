@@ -0,0 +1,112 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"sigs.k8s.io/external-dns/endpoint"
+	"sigs.k8s.io/external-dns/internal/testutils"
+)
+
+// Validates that apexTargetSource is a Source
+var _ Source = &apexTargetSource{}
+
+func TestApexTargetSourceEndpoints(t *testing.T) {
+	lookups := map[string][]net.IP{
+		"cdn.example.net": {net.ParseIP("1.2.3.4"), net.ParseIP("2001:db8::1")},
+		"lb.example.net":  {net.ParseIP("5.6.7.8")},
+	}
+	lookupIP := func(ctx context.Context, host string) ([]net.IP, error) {
+		if ips, ok := lookups[host]; ok {
+			return ips, nil
+		}
+		return nil, errors.New("no such host")
+	}
+
+	mockSource := new(testutils.MockSource)
+	mockSource.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeCNAME, 300, "cdn.example.net"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME, "cdn.example.net"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeCNAME, "lb.example.net"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeTXT, "\"some-text\""),
+	}, nil)
+
+	source := newApexTargetSource(mockSource, []string{"example.org", "Example.com."}, lookupIP)
+
+	endpoints, err := source.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeA, 300, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeAAAA, 300, "2001:db8::1"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME, "cdn.example.net"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "5.6.7.8"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeTXT, "\"some-text\""),
+	})
+
+	// A failing lookup keeps the addresses of the previous one.
+	delete(lookups, "lb.example.net")
+	endpoints, err = source.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeA, 300, "1.2.3.4"),
+		endpoint.NewEndpointWithTTL("example.org", endpoint.RecordTypeAAAA, 300, "2001:db8::1"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeCNAME, "cdn.example.net"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeA, "5.6.7.8"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeTXT, "\"some-text\""),
+	})
+}
+
+func TestApexTargetSourceUnresolvableTarget(t *testing.T) {
+	mockSource := new(testutils.MockSource)
+	mockSource.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("example.org", endpoint.RecordTypeCNAME, "unknown.example.net"),
+		endpoint.NewEndpoint("www.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	}, nil)
+
+	source := newApexTargetSource(mockSource, []string{"example.org"}, func(ctx context.Context, host string) ([]net.IP, error) {
+		return nil, errors.New("no such host")
+	})
+
+	// Without a previous lookup no endpoints are returned, so that the apex records are kept.
+	_, err := source.Endpoints(context.Background())
+	require.ErrorContains(t, err, "zone apex example.org")
+}
+
+func TestApexTargetSourceOnlyGivenApexes(t *testing.T) {
+	mockSource := new(testutils.MockSource)
+	mockSource.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("sub.example.org", endpoint.RecordTypeCNAME, "cdn.example.net"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeCNAME, "cdn.example.net"),
+	}, nil)
+
+	source := newApexTargetSource(mockSource, []string{"example.org"}, func(ctx context.Context, host string) ([]net.IP, error) {
+		return []net.IP{net.ParseIP("1.2.3.4")}, nil
+	})
+
+	endpoints, err := source.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("sub.example.org", endpoint.RecordTypeCNAME, "cdn.example.net"),
+		endpoint.NewEndpoint("example.com", endpoint.RecordTypeCNAME, "cdn.example.net"),
+	})
+}
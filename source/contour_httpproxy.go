@@ -150,8 +150,11 @@ func (sc *httpProxySource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint,
 			return nil, errors.Wrap(err, "failed to get endpoints from HTTPProxy")
 		}
 
-		// apply template if fqdn is missing on HTTPProxy
-		if (sc.combineFQDNAnnotation || len(hpEndpoints) == 0) && sc.fqdnTemplate != nil {
+		// apply template if fqdn is missing on HTTPProxy, except for the ones included by another
+		// HTTPProxy, as they are served under the fqdn of the including one
+		if hp.Spec.VirtualHost == nil && sc.fqdnTemplate != nil {
+			log.Debugf("Not applying template to HTTPProxy %s/%s because it has no virtual host", hp.Namespace, hp.Name)
+		} else if (sc.combineFQDNAnnotation || len(hpEndpoints) == 0) && sc.fqdnTemplate != nil {
 			tmplEndpoints, err := sc.endpointsFromTemplate(hp)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to get endpoints from template")
@@ -187,11 +190,6 @@ func (sc *httpProxySource) endpointsFromTemplate(httpProxy *projectcontour.HTTPP
 		return nil, err
 	}
 
-	ttl, err := getTTLFromAnnotations(httpProxy.Annotations)
-	if err != nil {
-		log.Warn(err)
-	}
-
 	targets := getTargetsFromTargetAnnotation(httpProxy.Annotations)
 	if len(targets) == 0 {
 		for _, lb := range httpProxy.Status.LoadBalancer.Ingress {
@@ -208,11 +206,20 @@ func (sc *httpProxySource) endpointsFromTemplate(httpProxy *projectcontour.HTTPP
 
 	var endpoints []*endpoint.Endpoint
 	for _, hostname := range hostnames {
-		endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier)...)
+		endpoints = append(endpoints, endpointsForHostname(hostname, targets, sc.ttlForHostname(httpProxy, hostname), providerSpecific, setIdentifier)...)
 	}
 	return endpoints, nil
 }
 
+// ttlForHostname returns the TTL of the given hostname of an HTTPProxy, like for an Ingress.
+func (sc *httpProxySource) ttlForHostname(httpProxy *projectcontour.HTTPProxy, hostname string) endpoint.TTL {
+	ttl, err := getTTLForHostnameFromAnnotations(httpProxy.Annotations, hostname)
+	if err != nil {
+		log.Warnf("Ignoring the TTL annotation of HTTPProxy %s/%s for %s: %v", httpProxy.Namespace, httpProxy.Name, hostname, err)
+	}
+	return ttl
+}
+
 // filterByAnnotations filters a list of configs by a given annotation selector.
 func (sc *httpProxySource) filterByAnnotations(httpProxies []*projectcontour.HTTPProxy) ([]*projectcontour.HTTPProxy, error) {
 	labelSelector, err := metav1.ParseToLabelSelector(sc.annotationFilter)
@@ -259,11 +266,6 @@ func (sc *httpProxySource) endpointsFromHTTPProxy(httpProxy *projectcontour.HTTP
 
 	var endpoints []*endpoint.Endpoint
 
-	ttl, err := getTTLFromAnnotations(httpProxy.Annotations)
-	if err != nil {
-		log.Warn(err)
-	}
-
 	targets := getTargetsFromTargetAnnotation(httpProxy.Annotations)
 
 	if len(targets) == 0 {
@@ -281,7 +283,7 @@ func (sc *httpProxySource) endpointsFromHTTPProxy(httpProxy *projectcontour.HTTP
 
 	if virtualHost := httpProxy.Spec.VirtualHost; virtualHost != nil {
 		if fqdn := virtualHost.Fqdn; fqdn != "" {
-			endpoints = append(endpoints, endpointsForHostname(fqdn, targets, ttl, providerSpecific, setIdentifier)...)
+			endpoints = append(endpoints, endpointsForHostname(fqdn, targets, sc.ttlForHostname(httpProxy, fqdn), providerSpecific, setIdentifier)...)
 		}
 	}
 
@@ -289,7 +291,7 @@ func (sc *httpProxySource) endpointsFromHTTPProxy(httpProxy *projectcontour.HTTP
 	if !sc.ignoreHostnameAnnotation {
 		hostnameList := getHostnamesFromAnnotations(httpProxy.Annotations)
 		for _, hostname := range hostnameList {
-			endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier)...)
+			endpoints = append(endpoints, endpointsForHostname(hostname, targets, sc.ttlForHostname(httpProxy, hostname), providerSpecific, setIdentifier)...)
 		}
 	}
 
@@ -898,6 +898,66 @@ func testHTTPProxyEndpoints(t *testing.T) {
 				},
 			},
 		},
+		{
+			title:           "httpproxy rules with hostname annotation and a TTL per hostname",
+			targetNamespace: "",
+			loadBalancer: fakeLoadBalancerService{
+				ips: []string{"8.8.8.8"},
+			},
+			httpProxyItems: []fakeHTTPProxy{
+				{
+					name:      "fake1",
+					namespace: namespace,
+					annotations: map[string]string{
+						hostnameAnnotationKey: "a.example.org,b.example.org",
+						ttlAnnotationKey:      "60,120",
+					},
+					host: "example.org",
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName: "example.org",
+					Targets: endpoint.Targets{"8.8.8.8"},
+				},
+				{
+					DNSName:   "a.example.org",
+					Targets:   endpoint.Targets{"8.8.8.8"},
+					RecordTTL: endpoint.TTL(60),
+				},
+				{
+					DNSName:   "b.example.org",
+					Targets:   endpoint.Targets{"8.8.8.8"},
+					RecordTTL: endpoint.TTL(120),
+				},
+			},
+		},
+		{
+			title:           "template not applied to included httpproxy",
+			targetNamespace: "",
+			loadBalancer: fakeLoadBalancerService{
+				ips: []string{"8.8.8.8"},
+			},
+			httpProxyItems: []fakeHTTPProxy{
+				{
+					name:      "root",
+					namespace: namespace,
+					host:      "example.org",
+				},
+				{
+					name:      "included",
+					namespace: namespace,
+					delegate:  true,
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName: "example.org",
+					Targets: endpoint.Targets{"8.8.8.8"},
+				},
+			},
+			fqdnTemplate: "{{.Name}}.ext-dns.test.com",
+		},
 		{
 			title:           "template for httpproxy with annotation",
 			targetNamespace: "",
@@ -362,6 +362,28 @@ func testCRDSourceEndpoints(t *testing.T) {
 			expectEndpoints: true,
 			expectError:     false,
 		},
+		{
+			title:                "valid crd gvk with provider specific properties",
+			registeredAPIVersion: "test.k8s.io/v1alpha1",
+			apiVersion:           "test.k8s.io/v1alpha1",
+			registeredKind:       "DNSEndpoint",
+			kind:                 "DNSEndpoint",
+			namespace:            "foo",
+			registeredNamespace:  "foo",
+			endpoints: []*endpoint.Endpoint{
+				{
+					DNSName:    "example.org",
+					Targets:    endpoint.Targets{"10 mail.example.org"},
+					RecordType: "MX",
+					RecordTTL:  180,
+					ProviderSpecific: endpoint.ProviderSpecific{
+						{Name: "cloudns/geo", Value: "country:DE"},
+					},
+				},
+			},
+			expectEndpoints: true,
+			expectError:     false,
+		},
 		{
 			title:                "Create NS record",
 			registeredAPIVersion: "test.k8s.io/v1alpha1",
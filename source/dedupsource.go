@@ -18,12 +18,29 @@ package source
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"strings"
 
+	"github.com/prometheus/client_golang/prometheus"
 	log "github.com/sirupsen/logrus"
 
 	"sigs.k8s.io/external-dns/endpoint"
 )
 
+var duplicateEndpointsTotal = prometheus.NewCounter(
+	prometheus.CounterOpts{
+		Namespace: "external_dns",
+		Subsystem: "source",
+		Name:      "duplicate_endpoints_total",
+		Help:      "Number of identical endpoints removed from the sources.",
+	},
+)
+
+func init() {
+	prometheus.MustRegister(duplicateEndpointsTotal)
+}
+
 // dedupSource is a Source that removes duplicate endpoints from its wrapped source.
 type dedupSource struct {
 	source Source
@@ -35,9 +52,13 @@ func NewDedupSource(source Source) Source {
 }
 
 // Endpoints collects endpoints from its wrapped source and returns them without duplicates.
+// Endpoints are duplicates when their name, record type, set identifier, targets, TTL and
+// provider specific properties are identical. The labels of duplicates are merged into the
+// endpoint which is kept.
 func (ms *dedupSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	result := []*endpoint.Endpoint{}
-	collected := map[string]bool{}
+	collected := map[string]*endpoint.Endpoint{}
+	resources := map[string][]string{}
 
 	endpoints, err := ms.source.Endpoints(ctx)
 	if err != nil {
@@ -45,20 +66,60 @@ func (ms *dedupSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, err
 	}
 
 	for _, ep := range endpoints {
-		identifier := ep.DNSName + " / " + ep.SetIdentifier + " / " + ep.Targets.String()
-
-		if _, ok := collected[identifier]; ok {
-			log.Debugf("Removing duplicate endpoint %s", ep)
+		identifier := dedupIdentifier(ep)
+
+		if existing, ok := collected[identifier]; ok {
+			duplicateEndpointsTotal.Inc()
+			mergeLabels(existing, ep)
+			if resource, ok := ep.Labels[endpoint.ResourceLabelKey]; ok {
+				resources[identifier] = append(resources[identifier], resource)
+			}
 			continue
 		}
 
-		collected[identifier] = true
+		collected[identifier] = ep
 		result = append(result, ep)
+		if resource, ok := ep.Labels[endpoint.ResourceLabelKey]; ok {
+			resources[identifier] = []string{resource}
+		}
+	}
+
+	for identifier, ep := range collected {
+		if len(resources[identifier]) > 1 {
+			log.Debugf("Removed duplicates of endpoint %s contributed by %s", ep, strings.Join(resources[identifier], ", "))
+		}
 	}
 
 	return result, nil
 }
 
+// dedupIdentifier returns a key which is identical for endpoints that only differ in their labels.
+func dedupIdentifier(ep *endpoint.Endpoint) string {
+	targets := make([]string, len(ep.Targets))
+	copy(targets, ep.Targets)
+	sort.Strings(targets)
+
+	properties := make([]string, 0, len(ep.ProviderSpecific))
+	for _, property := range ep.ProviderSpecific {
+		properties = append(properties, property.Name+"="+property.Value)
+	}
+	sort.Strings(properties)
+
+	return fmt.Sprintf("%s / %s / %s / %s / %d / %s", ep.DNSName, ep.RecordType, ep.SetIdentifier, strings.Join(targets, ";"), ep.RecordTTL, strings.Join(properties, ","))
+}
+
+// mergeLabels adds the labels of duplicate which are not set on ep yet.
+func mergeLabels(ep, duplicate *endpoint.Endpoint) {
+	for key, value := range duplicate.Labels {
+		if ep.Labels == nil {
+			ep.Labels = endpoint.NewLabels()
+		}
+		if _, ok := ep.Labels[key]; !ok {
+			ep.Labels[key] = value
+		}
+	}
+}
+
 func (ms *dedupSource) AddEventHandler(ctx context.Context, handler func()) {
 	ms.source.AddEventHandler(ctx, handler)
 }
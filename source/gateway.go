@@ -182,7 +182,7 @@ func (src *gatewayRouteSource) AddEventHandler(ctx context.Context, handler func
 	log.Debugf("Adding event handlers for %s", src.rtKind)
 	eventHandler := eventHandlerFunc(handler)
 	src.gwInformer.Informer().AddEventHandler(eventHandler)
-	src.rtInformer.Informer().AddEventHandler(eventHandler)
+	src.rtInformer.Informer().AddEventHandler(unlessExcludedEventHandlerFunc(handler))
 	src.nsInformer.Informer().AddEventHandler(eventHandler)
 }
 
@@ -217,6 +217,11 @@ func (src *gatewayRouteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpo
 			continue
 		}
 
+		if isExcluded(annots) {
+			log.Debugf("Skipping %s %s/%s because it is excluded from DNS management", src.rtKind, meta.Namespace, meta.Name)
+			continue
+		}
+
 		// Get Route hostnames and their targets.
 		hostTargets, err := resolver.resolve(rt)
 		if err != nil {
@@ -232,7 +237,7 @@ func (src *gatewayRouteSource) Endpoints(ctx context.Context) ([]*endpoint.Endpo
 		providerSpecific, setIdentifier := getProviderSpecificAnnotations(annots)
 		ttl, err := getTTLFromAnnotations(annots)
 		if err != nil {
-			log.Warn(err)
+			log.Warnf("Ignoring the TTL annotation of %s %s/%s: %v", src.rtKind, meta.Namespace, meta.Name, err)
 		}
 		for host, targets := range hostTargets {
 			eps := endpointsForHostname(host, targets, ttl, providerSpecific, setIdentifier)
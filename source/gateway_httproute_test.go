@@ -366,6 +366,32 @@ func TestGatewayHTTPRouteSourceEndpoints(t *testing.T) {
 			}},
 			endpoints: nil,
 		},
+		{
+			title:      "SkipExcludeAnnotation",
+			config:     Config{},
+			namespaces: namespaces("default"),
+			gateways: []*v1beta1.Gateway{{
+				ObjectMeta: objectMeta("default", "test"),
+				Spec: v1beta1.GatewaySpec{
+					Listeners: []v1beta1.Listener{{Protocol: v1beta1.HTTPProtocolType}},
+				},
+				Status: gatewayStatus("1.2.3.4"),
+			}},
+			routes: []*v1beta1.HTTPRoute{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "api",
+					Namespace: "default",
+					Annotations: map[string]string{
+						excludeAnnotationKey: "true",
+					},
+				},
+				Spec: v1beta1.HTTPRouteSpec{
+					Hostnames: hostnames("api.example.internal"),
+				},
+				Status: httpRouteStatus(gatewayParentRef("default", "test")),
+			}},
+			endpoints: nil,
+		},
 		{
 			title:      "MultipleGateways",
 			config:     Config{},
@@ -96,3 +96,97 @@ func TestGatewayTLSRouteSourceEndpoints(t *testing.T) {
 		newTestEndpoint("api-template.foobar.internal", "A", ips...),
 	})
 }
+
+func TestGatewayTLSRouteSourceWildcardsAndPendingGateways(t *testing.T) {
+	t.Parallel()
+
+	gwClient := gatewayfake.NewSimpleClientset()
+	kubeClient := kubefake.NewSimpleClientset()
+	clients := new(MockClientGenerator)
+	clients.On("GatewayClient").Return(gwClient, nil)
+	clients.On("KubeClient").Return(kubeClient, nil)
+
+	ctx := context.Background()
+	ns := &corev1.Namespace{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "default",
+		},
+	}
+	_, err := kubeClient.CoreV1().Namespaces().Create(ctx, ns, metav1.CreateOptions{})
+	require.NoError(t, err, "failed to create Namespace")
+
+	wildcard := v1beta1.Hostname("*.foobar.internal")
+	ips := []string{"10.64.0.1"}
+	gateways := []*v1beta1.Gateway{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "internal",
+				Namespace: "default",
+			},
+			Spec: v1beta1.GatewaySpec{
+				Listeners: []v1beta1.Listener{{
+					Protocol: v1beta1.TLSProtocolType,
+					Hostname: &wildcard,
+				}},
+			},
+			Status: gatewayStatus(ips...),
+		},
+		{
+			// A Gateway which didn't get any addresses assigned yet.
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pending",
+				Namespace: "default",
+			},
+			Spec: v1beta1.GatewaySpec{
+				Listeners: []v1beta1.Listener{{
+					Protocol: v1beta1.TLSProtocolType,
+				}},
+			},
+			Status: gatewayStatus(),
+		},
+	}
+	for _, gw := range gateways {
+		_, err = gwClient.GatewayV1beta1().Gateways(gw.Namespace).Create(ctx, gw, metav1.CreateOptions{})
+		require.NoError(t, err, "failed to create Gateway")
+	}
+
+	routes := []*v1alpha2.TLSRoute{
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "wildcard",
+				Namespace: "default",
+			},
+			Spec: v1alpha2.TLSRouteSpec{
+				Hostnames: []v1alpha2.Hostname{"*.foobar.internal"},
+			},
+			Status: v1alpha2.TLSRouteStatus{
+				RouteStatus: v1a2RouteStatus(v1a2ParentRef("default", "internal")),
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "pending",
+				Namespace: "default",
+			},
+			Spec: v1alpha2.TLSRouteSpec{
+				Hostnames: []v1alpha2.Hostname{"pending.foobar.internal"},
+			},
+			Status: v1alpha2.TLSRouteStatus{
+				RouteStatus: v1a2RouteStatus(v1a2ParentRef("default", "pending")),
+			},
+		},
+	}
+	for _, rt := range routes {
+		_, err = gwClient.GatewayV1alpha2().TLSRoutes(rt.Namespace).Create(ctx, rt, metav1.CreateOptions{})
+		require.NoError(t, err, "failed to create TLSRoute")
+	}
+
+	src, err := NewGatewayTLSRouteSource(clients, &Config{})
+	require.NoError(t, err, "failed to create Gateway TLSRoute Source")
+
+	endpoints, err := src.Endpoints(ctx)
+	require.NoError(t, err, "failed to get Endpoints")
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		newTestEndpoint("*.foobar.internal", "A", ips...),
+	})
+}
@@ -26,7 +26,6 @@ import (
 	log "github.com/sirupsen/logrus"
 	networkv1 "k8s.io/api/networking/v1"
 	"k8s.io/apimachinery/pkg/labels"
-	kubeinformers "k8s.io/client-go/informers"
 	netinformers "k8s.io/client-go/informers/networking/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/cache"
@@ -40,6 +39,9 @@ const (
 	// ALBDualstackAnnotationValue is the value of the ALB dualstack annotation that indicates it is dualstack
 	ALBDualstackAnnotationValue = "dualstack"
 
+	// IngressClassAnnotationKey is the legacy annotation used to select the class of an ingress
+	IngressClassAnnotationKey = "kubernetes.io/ingress.class"
+
 	// Possible values for the ingress-hostname-source annotation
 	IngressHostnameSourceAnnotationOnlyValue   = "annotation-only"
 	IngressHostnameSourceDefinedHostsOnlyValue = "defined-hosts-only"
@@ -60,18 +62,21 @@ type ingressSource struct {
 	ignoreIngressTLSSpec     bool
 	ignoreIngressRulesSpec   bool
 	labelSelector            labels.Selector
+	ingressClassNames        []string
+	preferLBTarget           string
 }
 
 // NewIngressSource creates a new ingressSource with the given config.
-func NewIngressSource(ctx context.Context, kubeClient kubernetes.Interface, namespace, annotationFilter string, fqdnTemplate string, combineFqdnAnnotation bool, ignoreHostnameAnnotation bool, ignoreIngressTLSSpec bool, ignoreIngressRulesSpec bool, labelSelector labels.Selector) (Source, error) {
+func NewIngressSource(ctx context.Context, kubeClient kubernetes.Interface, namespace, annotationFilter string, fqdnTemplate string, combineFqdnAnnotation bool, ignoreHostnameAnnotation bool, ignoreIngressTLSSpec bool, ignoreIngressRulesSpec bool, labelSelector labels.Selector, ingressClassNames []string, preferLBTarget string) (Source, error) {
 	tmpl, err := parseTemplate(fqdnTemplate)
 	if err != nil {
 		return nil, err
 	}
 
 	// Use shared informer to listen for add/update/delete of ingresses in the specified namespace.
+	// Only ingresses matching the label selector are listed from the API server.
 	// Set resync period to 0, to prevent processing when nothing has changed.
-	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
+	informerFactory := newLabelFilteredInformerFactory(kubeClient, namespace, labelSelector)
 	ingressInformer := informerFactory.Networking().V1().Ingresses()
 
 	// Add default resource event handlers to properly initialize informer.
@@ -100,6 +105,8 @@ func NewIngressSource(ctx context.Context, kubeClient kubernetes.Interface, name
 		ignoreIngressTLSSpec:     ignoreIngressTLSSpec,
 		ignoreIngressRulesSpec:   ignoreIngressRulesSpec,
 		labelSelector:            labelSelector,
+		ingressClassNames:        ingressClassNames,
+		preferLBTarget:           preferLBTarget,
 	}
 	return sc, nil
 }
@@ -115,6 +122,7 @@ func (sc *ingressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 	if err != nil {
 		return nil, err
 	}
+	ingresses = sc.filterByIngressClass(ingresses)
 
 	endpoints := []*endpoint.Endpoint{}
 
@@ -127,7 +135,12 @@ func (sc *ingressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 			continue
 		}
 
-		ingEndpoints := endpointsFromIngress(ing, sc.ignoreHostnameAnnotation, sc.ignoreIngressTLSSpec, sc.ignoreIngressRulesSpec)
+		if isExcluded(ing.Annotations) {
+			log.Debugf("Skipping ingress %s/%s because it is excluded from DNS management", ing.Namespace, ing.Name)
+			continue
+		}
+
+		ingEndpoints := endpointsFromIngress(ing, sc.ignoreHostnameAnnotation, sc.ignoreIngressTLSSpec, sc.ignoreIngressRulesSpec, sc.preferLBTarget)
 
 		// apply template if host is missing on ingress
 		if (sc.combineFQDNAnnotation || len(ingEndpoints) == 0) && sc.fqdnTemplate != nil {
@@ -137,7 +150,7 @@ func (sc *ingressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 			}
 
 			if sc.combineFQDNAnnotation {
-				ingEndpoints = append(ingEndpoints, iEndpoints...)
+				ingEndpoints = mergeEndpoints(ingEndpoints, iEndpoints)
 			} else {
 				ingEndpoints = iEndpoints
 			}
@@ -167,20 +180,19 @@ func (sc *ingressSource) endpointsFromTemplate(ing *networkv1.Ingress) ([]*endpo
 		return nil, err
 	}
 
-	ttl, err := getTTLFromAnnotations(ing.Annotations)
-	if err != nil {
-		log.Warn(err)
-	}
-
 	targets := getTargetsFromTargetAnnotation(ing.Annotations)
 	if len(targets) == 0 {
-		targets = targetsFromIngressStatus(ing.Status)
+		targets = targetsFromIngressStatus(ing, sc.preferLBTarget)
 	}
 
 	providerSpecific, setIdentifier := getProviderSpecificAnnotations(ing.Annotations)
 
 	var endpoints []*endpoint.Endpoint
 	for _, hostname := range hostnames {
+		ttl, err := getTTLForHostnameFromAnnotations(ing.Annotations, hostname)
+		if err != nil {
+			log.Warnf("Ignoring the TTL annotation of ingress %s/%s for %s: %v", ing.Namespace, ing.Name, hostname, err)
+		}
 		endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier)...)
 	}
 	return endpoints, nil
@@ -210,6 +222,53 @@ func (sc *ingressSource) filterByAnnotations(ingresses []*networkv1.Ingress) ([]
 	return filteredList, nil
 }
 
+// filterByIngressClass filters a list of ingresses by the configured ingress class names.
+// The class may be given by spec.ingressClassName or the legacy ingress class annotation,
+// an empty class name matches ingresses with neither of the two.
+func (sc *ingressSource) filterByIngressClass(ingresses []*networkv1.Ingress) []*networkv1.Ingress {
+	// no class names returns original list
+	if len(sc.ingressClassNames) == 0 {
+		return ingresses
+	}
+
+	classNames := make(map[string]struct{}, len(sc.ingressClassNames))
+	for _, name := range sc.ingressClassNames {
+		classNames[name] = struct{}{}
+	}
+
+	filteredList := []*networkv1.Ingress{}
+
+	for _, ingress := range ingresses {
+		if ingressMatchesClass(ingress, classNames) {
+			filteredList = append(filteredList, ingress)
+		} else {
+			log.Debugf("Skipping ingress %s/%s because its ingress class doesn't match", ingress.Namespace, ingress.Name)
+		}
+	}
+
+	return filteredList
+}
+
+func ingressMatchesClass(ingress *networkv1.Ingress, classNames map[string]struct{}) bool {
+	var classes []string
+	if ingress.Spec.IngressClassName != nil {
+		classes = append(classes, *ingress.Spec.IngressClassName)
+	}
+	if class, ok := ingress.Annotations[IngressClassAnnotationKey]; ok {
+		classes = append(classes, class)
+	}
+	if len(classes) == 0 {
+		classes = []string{""}
+	}
+
+	for _, class := range classes {
+		if _, ok := classNames[class]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (sc *ingressSource) setResourceLabel(ingress *networkv1.Ingress, endpoints []*endpoint.Endpoint) {
 	for _, ep := range endpoints {
 		ep.Labels[endpoint.ResourceLabelKey] = fmt.Sprintf("ingress/%s/%s", ingress.Namespace, ingress.Name)
@@ -227,16 +286,19 @@ func (sc *ingressSource) setDualstackLabel(ingress *networkv1.Ingress, endpoints
 }
 
 // endpointsFromIngress extracts the endpoints from ingress object
-func endpointsFromIngress(ing *networkv1.Ingress, ignoreHostnameAnnotation bool, ignoreIngressTLSSpec bool, ignoreIngressRulesSpec bool) []*endpoint.Endpoint {
-	ttl, err := getTTLFromAnnotations(ing.Annotations)
-	if err != nil {
-		log.Warn(err)
+func endpointsFromIngress(ing *networkv1.Ingress, ignoreHostnameAnnotation bool, ignoreIngressTLSSpec bool, ignoreIngressRulesSpec bool, preferLBTarget string) []*endpoint.Endpoint {
+	ttlForHostname := func(hostname string) endpoint.TTL {
+		ttl, err := getTTLForHostnameFromAnnotations(ing.Annotations, hostname)
+		if err != nil {
+			log.Warnf("Ignoring the TTL annotation of ingress %s/%s for %s: %v", ing.Namespace, ing.Name, hostname, err)
+		}
+		return ttl
 	}
 
 	targets := getTargetsFromTargetAnnotation(ing.Annotations)
 
 	if len(targets) == 0 {
-		targets = targetsFromIngressStatus(ing.Status)
+		targets = targetsFromIngressStatus(ing, preferLBTarget)
 	}
 
 	providerSpecific, setIdentifier := getProviderSpecificAnnotations(ing.Annotations)
@@ -249,7 +311,7 @@ func endpointsFromIngress(ing *networkv1.Ingress, ignoreHostnameAnnotation bool,
 			if rule.Host == "" {
 				continue
 			}
-			definedHostsEndpoints = append(definedHostsEndpoints, endpointsForHostname(rule.Host, targets, ttl, providerSpecific, setIdentifier)...)
+			definedHostsEndpoints = append(definedHostsEndpoints, endpointsForHostname(rule.Host, targets, ttlForHostname(rule.Host), providerSpecific, setIdentifier)...)
 		}
 	}
 
@@ -260,7 +322,7 @@ func endpointsFromIngress(ing *networkv1.Ingress, ignoreHostnameAnnotation bool,
 				if host == "" {
 					continue
 				}
-				definedHostsEndpoints = append(definedHostsEndpoints, endpointsForHostname(host, targets, ttl, providerSpecific, setIdentifier)...)
+				definedHostsEndpoints = append(definedHostsEndpoints, endpointsForHostname(host, targets, ttlForHostname(host), providerSpecific, setIdentifier)...)
 			}
 		}
 	}
@@ -269,7 +331,7 @@ func endpointsFromIngress(ing *networkv1.Ingress, ignoreHostnameAnnotation bool,
 	var annotationEndpoints []*endpoint.Endpoint
 	if !ignoreHostnameAnnotation {
 		for _, hostname := range getHostnamesFromAnnotations(ing.Annotations) {
-			annotationEndpoints = append(annotationEndpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier)...)
+			annotationEndpoints = append(annotationEndpoints, endpointsForHostname(hostname, targets, ttlForHostname(hostname), providerSpecific, setIdentifier)...)
 		}
 	}
 
@@ -290,19 +352,8 @@ func endpointsFromIngress(ing *networkv1.Ingress, ignoreHostnameAnnotation bool,
 	return endpoints
 }
 
-func targetsFromIngressStatus(status networkv1.IngressStatus) endpoint.Targets {
-	var targets endpoint.Targets
-
-	for _, lb := range status.LoadBalancer.Ingress {
-		if lb.IP != "" {
-			targets = append(targets, lb.IP)
-		}
-		if lb.Hostname != "" {
-			targets = append(targets, lb.Hostname)
-		}
-	}
-
-	return targets
+func targetsFromIngressStatus(ing *networkv1.Ingress, preferLBTarget string) endpoint.Targets {
+	return targetsFromLoadBalancerStatus(ing.Status.LoadBalancer, preferLBTarget, fmt.Sprintf("ingress %s/%s", ing.Namespace, ing.Name))
 }
 
 func (sc *ingressSource) AddEventHandler(ctx context.Context, handler func()) {
@@ -310,5 +361,5 @@ func (sc *ingressSource) AddEventHandler(ctx context.Context, handler func()) {
 
 	// Right now there is no way to remove event handler from informer, see:
 	// https://github.com/kubernetes/kubernetes/issues/79610
-	sc.ingressInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	sc.ingressInformer.Informer().AddEventHandler(unlessExcludedEventHandlerFunc(handler))
 }
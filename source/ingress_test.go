@@ -66,6 +66,8 @@ func (suite *IngressSuite) SetupTest() {
 		false,
 		false,
 		labels.Everything(),
+		nil,
+		LoadBalancerTargetBoth,
 	)
 	suite.NoError(err, "should initialize ingress source")
 }
@@ -149,6 +151,8 @@ func TestNewIngressSource(t *testing.T) {
 				false,
 				false,
 				labels.Everything(),
+				nil,
+				LoadBalancerTargetBoth,
 			)
 			if ti.expectError {
 				assert.Error(t, err)
@@ -250,7 +254,7 @@ func testEndpointsFromIngress(t *testing.T) {
 	} {
 		t.Run(ti.title, func(t *testing.T) {
 			realIngress := ti.ingress.Ingress()
-			validateEndpoints(t, endpointsFromIngress(realIngress, ti.ignoreHostnameAnnotation, ti.ignoreIngressTLSSpec, ti.ignoreIngressRulesSpec), ti.expected)
+			validateEndpoints(t, endpointsFromIngress(realIngress, ti.ignoreHostnameAnnotation, ti.ignoreIngressTLSSpec, ti.ignoreIngressRulesSpec, LoadBalancerTargetBoth), ti.expected)
 		})
 	}
 }
@@ -342,7 +346,7 @@ func testEndpointsFromIngressHostnameSourceAnnotation(t *testing.T) {
 	} {
 		t.Run(ti.title, func(t *testing.T) {
 			realIngress := ti.ingress.Ingress()
-			validateEndpoints(t, endpointsFromIngress(realIngress, false, false, false), ti.expected)
+			validateEndpoints(t, endpointsFromIngress(realIngress, false, false, false, LoadBalancerTargetBoth), ti.expected)
 		})
 	}
 }
@@ -364,6 +368,7 @@ func testIngressEndpoints(t *testing.T) {
 		ignoreIngressTLSSpec     bool
 		ignoreIngressRulesSpec   bool
 		ingressLabelSelector     labels.Selector
+		ingressClassNames        []string
 	}{
 		{
 			title:           "no ingress",
@@ -602,6 +607,36 @@ func testIngressEndpoints(t *testing.T) {
 			},
 			expected: []*endpoint.Endpoint{},
 		},
+		{
+			title:           "excluded ingresses are ignored",
+			targetNamespace: "",
+			ingressItems: []fakeIngress{
+				{
+					name:      "fake1",
+					namespace: namespace,
+					annotations: map[string]string{
+						excludeAnnotationKey: "true",
+					},
+					dnsnames: []string{"example.org"},
+					ips:      []string{"8.8.8.8"},
+				},
+				{
+					name:      "fake2",
+					namespace: namespace,
+					annotations: map[string]string{
+						excludeAnnotationKey: "false",
+					},
+					dnsnames: []string{"new.org"},
+					ips:      []string{"8.8.4.4"},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName: "new.org",
+					Targets: endpoint.Targets{"8.8.4.4"},
+				},
+			},
+		},
 		{
 			title:           "template for ingress if host is missing",
 			targetNamespace: "",
@@ -1210,6 +1245,87 @@ func testIngressEndpoints(t *testing.T) {
 			},
 			expected: []*endpoint.Endpoint{},
 		},
+		{
+			title:             "ingress class filter matches spec and annotation",
+			targetNamespace:   "",
+			ingressClassNames: []string{"nginx"},
+			ingressItems: []fakeIngress{
+				{
+					name:             "fake-spec",
+					namespace:        namespace,
+					dnsnames:         []string{"spec.example.org"},
+					ips:              []string{"8.8.8.8"},
+					ingressClassName: "nginx",
+				},
+				{
+					name:        "fake-annotation",
+					namespace:   namespace,
+					dnsnames:    []string{"annotation.example.org"},
+					ips:         []string{"8.8.8.8"},
+					annotations: map[string]string{IngressClassAnnotationKey: "nginx"},
+				},
+				{
+					name:             "fake-internal",
+					namespace:        namespace,
+					dnsnames:         []string{"internal.example.org"},
+					ips:              []string{"8.8.8.8"},
+					ingressClassName: "internal",
+				},
+				{
+					name:      "fake-no-class",
+					namespace: namespace,
+					dnsnames:  []string{"no-class.example.org"},
+					ips:       []string{"8.8.8.8"},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName: "spec.example.org",
+					Targets: endpoint.Targets{"8.8.8.8"},
+				},
+				{
+					DNSName: "annotation.example.org",
+					Targets: endpoint.Targets{"8.8.8.8"},
+				},
+			},
+		},
+		{
+			title:             "empty ingress class filter matches ingresses without class",
+			targetNamespace:   "",
+			ingressClassNames: []string{"", "internal"},
+			ingressItems: []fakeIngress{
+				{
+					name:             "fake-nginx",
+					namespace:        namespace,
+					dnsnames:         []string{"nginx.example.org"},
+					ips:              []string{"8.8.8.8"},
+					ingressClassName: "nginx",
+				},
+				{
+					name:        "fake-internal",
+					namespace:   namespace,
+					dnsnames:    []string{"internal.example.org"},
+					ips:         []string{"8.8.8.8"},
+					annotations: map[string]string{IngressClassAnnotationKey: "internal"},
+				},
+				{
+					name:      "fake-no-class",
+					namespace: namespace,
+					dnsnames:  []string{"no-class.example.org"},
+					ips:       []string{"8.8.8.8"},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName: "internal.example.org",
+					Targets: endpoint.Targets{"8.8.8.8"},
+				},
+				{
+					DNSName: "no-class.example.org",
+					Targets: endpoint.Targets{"8.8.8.8"},
+				},
+			},
+		},
 	} {
 		ti := ti
 		t.Run(ti.title, func(t *testing.T) {
@@ -1237,6 +1353,8 @@ func testIngressEndpoints(t *testing.T) {
 				ti.ignoreIngressTLSSpec,
 				ti.ignoreIngressRulesSpec,
 				ti.ingressLabelSelector,
+				ti.ingressClassNames,
+				LoadBalancerTargetBoth,
 			)
 			// Informer cache has all of the ingresses. Retrieve and validate their endpoints.
 			res, err := source.Endpoints(context.Background())
@@ -1260,6 +1378,8 @@ type fakeIngress struct {
 	name        string
 	annotations map[string]string
 	labels      map[string]string
+	// ingressClassName sets spec.ingressClassName if not empty
+	ingressClassName string
 }
 
 func (ing fakeIngress) Ingress() *networkv1.Ingress {
@@ -1279,6 +1399,9 @@ func (ing fakeIngress) Ingress() *networkv1.Ingress {
 			},
 		},
 	}
+	if ing.ingressClassName != "" {
+		ingress.Spec.IngressClassName = &ing.ingressClassName
+	}
 	for _, dnsname := range ing.dnsnames {
 		ingress.Spec.Rules = append(ingress.Spec.Rules, networkv1.IngressRule{
 			Host: dnsname,
@@ -73,7 +73,7 @@ func NewIstioGatewaySource(
 	// Set resync period to 0, to prevent processing when nothing has changed
 	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
 	serviceInformer := informerFactory.Core().V1().Services()
-	istioInformerFactory := istioinformers.NewSharedInformerFactory(istioClient, 0)
+	istioInformerFactory := istioinformers.NewSharedInformerFactoryWithOptions(istioClient, 0, istioinformers.WithNamespace(namespace))
 	gatewayInformer := istioInformerFactory.Networking().V1alpha3().Gateways()
 
 	// Add default resource event handlers to properly initialize informer.
@@ -120,12 +120,15 @@ func NewIstioGatewaySource(
 // Endpoints returns endpoint objects for each host-target combination that should be processed.
 // Retrieves all gateway resources in the source's namespace(s).
 func (sc *gatewaySource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
-	gwList, err := sc.istioClient.NetworkingV1alpha3().Gateways(sc.namespace).List(ctx, metav1.ListOptions{})
+	gwList, err := sc.gatewayInformer.Lister().Gateways(sc.namespace).List(labels.Everything())
 	if err != nil {
 		return nil, err
 	}
 
-	gateways := gwList.Items
+	gateways := make([]networkingv1alpha3.Gateway, 0, len(gwList))
+	for _, gw := range gwList {
+		gateways = append(gateways, *gw)
+	}
 	gateways, err = sc.filterByAnnotations(gateways)
 	if err != nil {
 		return nil, err
@@ -268,7 +271,7 @@ func (sc *gatewaySource) endpointsFromGateway(hostnames []string, gateway networ
 	annotations := gateway.Annotations
 	ttl, err := getTTLFromAnnotations(annotations)
 	if err != nil {
-		log.Warn(err)
+		log.Warnf("Ignoring the TTL annotation of gateway %s/%s: %v", gateway.Namespace, gateway.Name, err)
 	}
 
 	targets := getTargetsFromTargetAnnotation(annotations)
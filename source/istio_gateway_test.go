@@ -19,6 +19,7 @@ package source
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/stretchr/testify/assert"
@@ -26,9 +27,13 @@ import (
 	"github.com/stretchr/testify/suite"
 	networkingv1alpha3api "istio.io/api/networking/v1alpha3"
 	networkingv1alpha3 "istio.io/client-go/pkg/apis/networking/v1alpha3"
+	istioclient "istio.io/client-go/pkg/clientset/versioned"
 	istiofake "istio.io/client-go/pkg/clientset/versioned/fake"
+	networkingv1alpha3informer "istio.io/client-go/pkg/informers/externalversions/networking/v1alpha3"
+	networkingv1alpha3lister "istio.io/client-go/pkg/listers/networking/v1alpha3"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"sigs.k8s.io/external-dns/endpoint"
@@ -161,6 +166,144 @@ func TestNewIstioGatewaySource(t *testing.T) {
 	}
 }
 
+func TestGatewaySourceEndpointsFromCache(t *testing.T) {
+	t.Parallel()
+
+	fakeKubernetesClient := fake.NewSimpleClientset()
+	service := fakeIngressGatewayService{
+		hostnames: []string{"lb.com"},
+		namespace: "istio-system",
+		name:      "istio-ingressgateway",
+	}.Service()
+	_, err := fakeKubernetesClient.CoreV1().Services(service.Namespace).Create(context.Background(), service, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	fakeIstioClient := istiofake.NewSimpleClientset()
+	gw := fakeGatewayConfig{
+		name:      "foo",
+		namespace: "istio-system",
+		dnsnames:  [][]string{{"foo.bar"}},
+	}.Config()
+	_, err = fakeIstioClient.NetworkingV1alpha3().Gateways(gw.Namespace).Create(context.Background(), &gw, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	gatewaySource, err := NewIstioGatewaySource(context.TODO(), fakeKubernetesClient, fakeIstioClient, "", "", "", false, false)
+	require.NoError(t, err)
+
+	// Endpoints are computed from the informer caches only.
+	fakeKubernetesClient.ClearActions()
+	fakeIstioClient.ClearActions()
+	endpoints, err := gatewaySource.Endpoints(context.Background())
+	require.NoError(t, err)
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		{DNSName: "foo.bar", RecordType: endpoint.RecordTypeCNAME, Targets: endpoint.Targets{"lb.com"}},
+	})
+	assert.Empty(t, fakeKubernetesClient.Actions())
+	assert.Empty(t, fakeIstioClient.Actions())
+
+	// Changes are picked up by the informer.
+	gw.Spec.Servers[0].Hosts = []string{"baz.bar"}
+	_, err = fakeIstioClient.NetworkingV1alpha3().Gateways(gw.Namespace).Update(context.Background(), &gw, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		endpoints, err := gatewaySource.Endpoints(context.Background())
+		require.NoError(t, err)
+		return len(endpoints) == 1 && endpoints[0].DNSName == "baz.bar"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestGatewaySourceListAndInformerAgree(t *testing.T) {
+	t.Parallel()
+
+	lbServices := []fakeIngressGatewayService{
+		{
+			ips:       []string{"8.8.8.8"},
+			namespace: "istio-system",
+			name:      "istio-ingressgateway",
+			selector:  map[string]string{"istio": "ingressgateway"},
+		},
+		{
+			hostnames: []string{"lb.example.net"},
+			namespace: "testing",
+			name:      "internal-gateway",
+			selector:  map[string]string{"istio": "internal"},
+		},
+	}
+	gateways := []fakeGatewayConfig{
+		{
+			name:      "public",
+			namespace: "istio-system",
+			dnsnames:  [][]string{{"example.org", "*/www.example.org"}},
+			selector:  map[string]string{"istio": "ingressgateway"},
+		},
+		{
+			name:        "internal",
+			namespace:   "testing",
+			annotations: map[string]string{targetAnnotationKey: "1.2.3.4"},
+			dnsnames:    [][]string{{"internal.example.org"}},
+			selector:    map[string]string{"istio": "internal"},
+		},
+		{
+			name:        "templated",
+			namespace:   "testing",
+			annotations: map[string]string{"kubernetes.io/ingress.class": "internal"},
+			selector:    map[string]string{"istio": "internal"},
+		},
+	}
+
+	for _, ti := range []struct {
+		title            string
+		targetNamespace  string
+		annotationFilter string
+		fqdnTemplate     string
+	}{
+		{
+			title: "all namespaces",
+		},
+		{
+			title:           "single namespace",
+			targetNamespace: "testing",
+		},
+		{
+			title:            "annotation filter and template",
+			annotationFilter: "kubernetes.io/ingress.class=internal",
+			fqdnTemplate:     "{{.Name}}.ext-dns.test.com",
+		},
+	} {
+		ti := ti
+		t.Run(ti.title, func(t *testing.T) {
+			t.Parallel()
+
+			fakeKubernetesClient := fake.NewSimpleClientset()
+			for _, lb := range lbServices {
+				service := lb.Service()
+				_, err := fakeKubernetesClient.CoreV1().Services(service.Namespace).Create(context.Background(), service, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			fakeIstioClient := istiofake.NewSimpleClientset()
+			for _, gw := range gateways {
+				gwObj := gw.Config()
+				_, err := fakeIstioClient.NetworkingV1alpha3().Gateways(gw.namespace).Create(context.Background(), &gwObj, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			src, err := NewIstioGatewaySource(context.TODO(), fakeKubernetesClient, fakeIstioClient, ti.targetNamespace, ti.annotationFilter, ti.fqdnTemplate, false, false)
+			require.NoError(t, err)
+			gatewaySource := src.(*gatewaySource)
+
+			fromInformer, err := gatewaySource.Endpoints(context.Background())
+			require.NoError(t, err)
+			require.NotEmpty(t, fromInformer)
+
+			gatewaySource.gatewayInformer = listGatewayInformer{gatewaySource.gatewayInformer, fakeIstioClient}
+			fromList, err := gatewaySource.Endpoints(context.Background())
+			require.NoError(t, err)
+
+			validateEndpoints(t, fromInformer, fromList)
+		})
+	}
+}
+
 func testEndpointsFromGatewayConfig(t *testing.T) {
 	t.Parallel()
 
@@ -1225,6 +1368,42 @@ func newTestGatewaySource(loadBalancerList []fakeIngressGatewayService) (*gatewa
 	return gwsrc, nil
 }
 
+// listGatewayInformer serves the gateways straight from the API, the way the sources read them
+// before they were cached, to check that both ways produce the same endpoints.
+type listGatewayInformer struct {
+	networkingv1alpha3informer.GatewayInformer
+	istioClient istioclient.Interface
+}
+
+func (i listGatewayInformer) Lister() networkingv1alpha3lister.GatewayLister {
+	return listGatewayLister{istioClient: i.istioClient}
+}
+
+type listGatewayLister struct {
+	istioClient istioclient.Interface
+	namespace   string
+}
+
+func (l listGatewayLister) List(selector labels.Selector) ([]*networkingv1alpha3.Gateway, error) {
+	gwList, err := l.istioClient.NetworkingV1alpha3().Gateways(l.namespace).List(context.Background(), metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, err
+	}
+	gateways := make([]*networkingv1alpha3.Gateway, 0, len(gwList.Items))
+	for i := range gwList.Items {
+		gateways = append(gateways, &gwList.Items[i])
+	}
+	return gateways, nil
+}
+
+func (l listGatewayLister) Gateways(namespace string) networkingv1alpha3lister.GatewayNamespaceLister {
+	return listGatewayLister{istioClient: l.istioClient, namespace: namespace}
+}
+
+func (l listGatewayLister) Get(name string) (*networkingv1alpha3.Gateway, error) {
+	return l.istioClient.NetworkingV1alpha3().Gateways(l.namespace).Get(context.Background(), name, metav1.GetOptions{})
+}
+
 type fakeIngressGatewayService struct {
 	ips       []string
 	hostnames []string
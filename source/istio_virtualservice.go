@@ -55,6 +55,7 @@ type virtualServiceSource struct {
 	ignoreHostnameAnnotation bool
 	serviceInformer          coreinformers.ServiceInformer
 	virtualserviceInformer   networkingv1alpha3informer.VirtualServiceInformer
+	gatewayInformer          networkingv1alpha3informer.GatewayInformer
 }
 
 // NewIstioVirtualServiceSource creates a new virtualServiceSource with the given config.
@@ -79,6 +80,7 @@ func NewIstioVirtualServiceSource(
 	serviceInformer := informerFactory.Core().V1().Services()
 	istioInformerFactory := istioinformers.NewSharedInformerFactoryWithOptions(istioClient, 0, istioinformers.WithNamespace(namespace))
 	virtualServiceInformer := istioInformerFactory.Networking().V1alpha3().VirtualServices()
+	gatewayInformer := istioInformerFactory.Networking().V1alpha3().Gateways()
 
 	// Add default resource event handlers to properly initialize informer.
 	serviceInformer.Informer().AddEventHandler(
@@ -97,6 +99,14 @@ func NewIstioVirtualServiceSource(
 		},
 	)
 
+	gatewayInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+				log.Debug("gateway added")
+			},
+		},
+	)
+
 	informerFactory.Start(ctx.Done())
 	istioInformerFactory.Start(ctx.Done())
 
@@ -118,6 +128,7 @@ func NewIstioVirtualServiceSource(
 		ignoreHostnameAnnotation: ignoreHostnameAnnotation,
 		serviceInformer:          serviceInformer,
 		virtualserviceInformer:   virtualServiceInformer,
+		gatewayInformer:          gatewayInformer,
 	}, nil
 }
 
@@ -180,11 +191,13 @@ func (sc *virtualServiceSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 	return endpoints, nil
 }
 
-// AddEventHandler adds an event handler that should be triggered if the watched Istio VirtualService changes.
+// AddEventHandler adds an event handler that should be triggered if the watched Istio VirtualService
+// or Gateway changes.
 func (sc *virtualServiceSource) AddEventHandler(ctx context.Context, handler func()) {
 	log.Debug("Adding event handler for Istio VirtualService")
 
 	sc.virtualserviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	sc.gatewayInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
 }
 
 func (sc *virtualServiceSource) getGateway(ctx context.Context, gatewayStr string, virtualService *networkingv1alpha3.VirtualService) (*networkingv1alpha3.Gateway, error) {
@@ -202,7 +215,13 @@ func (sc *virtualServiceSource) getGateway(ctx context.Context, gatewayStr strin
 		namespace = virtualService.Namespace
 	}
 
-	gateway, err := sc.istioClient.NetworkingV1alpha3().Gateways(namespace).Get(ctx, name, metav1.GetOptions{})
+	var gateway *networkingv1alpha3.Gateway
+	if sc.namespace == "" || namespace == sc.namespace {
+		gateway, err = sc.gatewayInformer.Lister().Gateways(namespace).Get(name)
+	} else {
+		// Only the gateways of the watched namespace are cached.
+		gateway, err = sc.istioClient.NetworkingV1alpha3().Gateways(namespace).Get(ctx, name, metav1.GetOptions{})
+	}
 	if err != nil {
 		log.Errorf("Failed retrieving gateway %s referenced by VirtualService %s/%s: %v", gatewayStr, virtualService.Namespace, virtualService.Name, err)
 		return nil, err
@@ -223,7 +242,7 @@ func (sc *virtualServiceSource) endpointsFromTemplate(ctx context.Context, virtu
 
 	ttl, err := getTTLFromAnnotations(virtualService.Annotations)
 	if err != nil {
-		log.Warn(err)
+		log.Warnf("Ignoring the TTL annotation of VirtualService %s/%s: %v", virtualService.Namespace, virtualService.Name, err)
 	}
 
 	providerSpecific, setIdentifier := getProviderSpecificAnnotations(virtualService.Annotations)
@@ -318,7 +337,7 @@ func (sc *virtualServiceSource) endpointsFromVirtualService(ctx context.Context,
 
 	ttl, err := getTTLFromAnnotations(virtualservice.Annotations)
 	if err != nil {
-		log.Warn(err)
+		log.Warnf("Ignoring the TTL annotation of VirtualService %s/%s: %v", virtualservice.Namespace, virtualservice.Name, err)
 	}
 
 	targetsFromAnnotation := getTargetsFromTargetAnnotation(virtualservice.Annotations)
@@ -19,6 +19,7 @@ package source
 import (
 	"context"
 	"testing"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
@@ -184,6 +185,132 @@ func TestNewIstioVirtualServiceSource(t *testing.T) {
 	}
 }
 
+func TestVirtualServiceSourceListAndInformerAgree(t *testing.T) {
+	t.Parallel()
+
+	lbServices := []fakeIngressGatewayService{
+		{
+			ips:       []string{"8.8.8.8"},
+			namespace: "testing",
+			name:      "ingressgateway",
+			selector:  map[string]string{"istio": "ingressgateway"},
+		},
+	}
+	gateways := []fakeGatewayConfig{
+		{
+			name:      "shared",
+			namespace: "istio-system",
+			dnsnames:  [][]string{{"*"}},
+			selector:  map[string]string{"istio": "ingressgateway"},
+		},
+		{
+			name:        "local",
+			namespace:   "testing",
+			annotations: map[string]string{targetAnnotationKey: "lb.example.net"},
+			dnsnames:    [][]string{{"*/*.example.org"}},
+		},
+	}
+	virtualServices := []fakeVirtualServiceConfig{
+		{
+			name:      "shared",
+			namespace: "testing",
+			gateways:  []string{"istio-system/shared"},
+			dnsnames:  []string{"shared.example.org"},
+		},
+		{
+			name:      "local",
+			namespace: "testing",
+			gateways:  []string{"local"},
+			dnsnames:  []string{"local.example.org"},
+		},
+		{
+			name:      "other",
+			namespace: "other",
+			gateways:  []string{"istio-system/shared"},
+			dnsnames:  []string{"other.example.org"},
+		},
+	}
+
+	for _, ti := range []struct {
+		title           string
+		targetNamespace string
+	}{
+		{
+			title: "all namespaces",
+		},
+		{
+			title:           "gateways outside of the watched namespace",
+			targetNamespace: "testing",
+		},
+	} {
+		ti := ti
+		t.Run(ti.title, func(t *testing.T) {
+			t.Parallel()
+
+			fakeKubernetesClient := fake.NewSimpleClientset()
+			for _, lb := range lbServices {
+				service := lb.Service()
+				_, err := fakeKubernetesClient.CoreV1().Services(service.Namespace).Create(context.Background(), service, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			fakeIstioClient := istiofake.NewSimpleClientset()
+			for _, gw := range gateways {
+				gwObj := gw.Config()
+				_, err := fakeIstioClient.NetworkingV1alpha3().Gateways(gw.namespace).Create(context.Background(), &gwObj, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			for _, vs := range virtualServices {
+				_, err := fakeIstioClient.NetworkingV1alpha3().VirtualServices(vs.namespace).Create(context.Background(), vs.Config(), metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			src, err := NewIstioVirtualServiceSource(context.TODO(), fakeKubernetesClient, fakeIstioClient, ti.targetNamespace, "", "", false, false)
+			require.NoError(t, err)
+			virtualServiceSource := src.(*virtualServiceSource)
+
+			fromInformer, err := virtualServiceSource.Endpoints(context.Background())
+			require.NoError(t, err)
+			require.NotEmpty(t, fromInformer)
+
+			virtualServiceSource.gatewayInformer = listGatewayInformer{virtualServiceSource.gatewayInformer, fakeIstioClient}
+			fromList, err := virtualServiceSource.Endpoints(context.Background())
+			require.NoError(t, err)
+
+			validateEndpoints(t, fromInformer, fromList)
+		})
+	}
+}
+
+func TestVirtualServiceSourceGatewayChangeTriggersHandler(t *testing.T) {
+	t.Parallel()
+
+	fakeIstioClient := istiofake.NewSimpleClientset()
+	src, err := NewIstioVirtualServiceSource(context.TODO(), fake.NewSimpleClientset(), fakeIstioClient, "", "", "", false, false)
+	require.NoError(t, err)
+
+	triggered := make(chan struct{}, 1)
+	src.AddEventHandler(context.Background(), func() {
+		select {
+		case triggered <- struct{}{}:
+		default:
+		}
+	})
+
+	gw := fakeGatewayConfig{
+		name:      "foo",
+		namespace: "istio-system",
+		dnsnames:  [][]string{{"foo.bar"}},
+	}.Config()
+	_, err = fakeIstioClient.NetworkingV1alpha3().Gateways(gw.Namespace).Create(context.Background(), &gw, metav1.CreateOptions{})
+	require.NoError(t, err)
+
+	select {
+	case <-triggered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("adding a gateway did not trigger the event handler")
+	}
+}
+
 func testVirtualServiceBindsToGateway(t *testing.T) {
 	for _, ti := range []struct {
 		title    string
@@ -553,9 +680,13 @@ func testEndpointsFromVirtualServiceConfig(t *testing.T) {
 		t.Run(ti.title, func(t *testing.T) {
 			t.Parallel()
 
-			if source, err := newTestVirtualServiceSource(ti.lbServices, []fakeGatewayConfig{ti.gwconfig}); err != nil {
+			gwconfig, vsconfig := ti.gwconfig, ti.vsconfig
+			gwconfig.namespace = "istio-system"
+			vsconfig.namespace = "istio-system"
+
+			if source, err := newTestVirtualServiceSource(ti.lbServices, []fakeGatewayConfig{gwconfig}); err != nil {
 				require.NoError(t, err)
-			} else if endpoints, err := source.endpointsFromVirtualService(context.Background(), ti.vsconfig.Config()); err != nil {
+			} else if endpoints, err := source.endpointsFromVirtualService(context.Background(), vsconfig.Config()); err != nil {
 				require.NoError(t, err)
 			} else {
 				validateEndpoints(t, endpoints, ti.expected)
@@ -52,13 +52,22 @@ type kongTCPIngressSource struct {
 	kongTCPIngressInformer informers.GenericInformer
 	kubeClient             kubernetes.Interface
 	namespace              string
+	kongProxy              *proxyService
+	preferLBTarget         string
 	unstructuredConverter  *unstructuredConverter
 }
 
-// NewKongTCPIngressSource creates a new kongTCPIngressSource with the given config.
-func NewKongTCPIngressSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string) (Source, error) {
+// NewKongTCPIngressSource creates a new kongTCPIngressSource with the given config. kongProxyService
+// is the namespace/name of the Service exposing the Kong proxy, used for TCPIngresses without targets.
+// preferLBTarget is the LoadBalancerTarget preference for the load balancer addresses.
+func NewKongTCPIngressSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string, kongProxyService string, preferLBTarget string) (Source, error) {
 	var err error
 
+	kongProxy, err := newProxyService(ctx, kubeClient, "Kong proxy", kongProxyService, preferLBTarget)
+	if err != nil {
+		return nil, err
+	}
+
 	// Use shared informer to listen for add/update/delete of Host in the specified namespace.
 	// Set resync period to 0, to prevent processing when nothing has changed.
 	informerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicKubeClient, 0, namespace, nil)
@@ -90,6 +99,8 @@ func NewKongTCPIngressSource(ctx context.Context, dynamicKubeClient dynamic.Inte
 		kongTCPIngressInformer: kongTCPIngressInformer,
 		kubeClient:             kubeClient,
 		namespace:              namespace,
+		kongProxy:              kongProxy,
+		preferLBTarget:         preferLBTarget,
 		unstructuredConverter:  uc,
 	}, nil
 }
@@ -122,19 +133,12 @@ func (sc *kongTCPIngressSource) Endpoints(ctx context.Context) ([]*endpoint.Endp
 		return nil, errors.Wrap(err, "failed to filter TCPIngresses")
 	}
 
+	proxyTargets := sc.kongProxy.targets()
+
 	var endpoints []*endpoint.Endpoint
 	for _, tcpIngress := range tcpIngresses {
-		var targets endpoint.Targets
-		for _, lb := range tcpIngress.Status.LoadBalancer.Ingress {
-			if lb.IP != "" {
-				targets = append(targets, lb.IP)
-			}
-			if lb.Hostname != "" {
-				targets = append(targets, lb.Hostname)
-			}
-		}
-
 		fullname := fmt.Sprintf("%s/%s", tcpIngress.Namespace, tcpIngress.Name)
+		targets := kongTargets(tcpIngress.Annotations, tcpIngress.Status.LoadBalancer, proxyTargets, sc.preferLBTarget, "tcpingress "+fullname)
 
 		ingressEndpoints, err := sc.endpointsFromTCPIngress(tcpIngress, targets)
 		if err != nil {
@@ -213,31 +217,49 @@ func (sc *kongTCPIngressSource) endpointsFromTCPIngress(tcpIngress *TCPIngress,
 
 	ttl, err := getTTLFromAnnotations(tcpIngress.Annotations)
 	if err != nil {
-		return nil, err
+		log.Warnf("Ignoring the TTL annotation of TCPIngress %s/%s: %v", tcpIngress.Namespace, tcpIngress.Name, err)
 	}
 
 	hostnameList := getHostnamesFromAnnotations(tcpIngress.Annotations)
-	for _, hostname := range hostnameList {
-		endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier)...)
+	for _, rule := range tcpIngress.Spec.Rules {
+		if rule.Host != "" {
+			hostnameList = append(hostnameList, rule.Host)
+		}
 	}
 
-	if tcpIngress.Spec.Rules != nil {
-		for _, rule := range tcpIngress.Spec.Rules {
-			if rule.Host != "" {
-				endpoints = append(endpoints, endpointsForHostname(rule.Host, targets, ttl, providerSpecific, setIdentifier)...)
-			}
+	// Rules for several ports commonly share a host.
+	seen := map[string]struct{}{}
+	for _, hostname := range hostnameList {
+		if _, ok := seen[hostname]; ok {
+			continue
 		}
+		seen[hostname] = struct{}{}
+		endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier)...)
 	}
 
 	return endpoints, nil
 }
 
+// kongTargets returns the targets of a Kong resource: those of its target annotation, else its own
+// load balancer addresses, else the ones of the Kong proxy Service. resource names the Kong resource
+// in the warning about its load balancer addresses.
+func kongTargets(annotations map[string]string, status corev1.LoadBalancerStatus, proxyTargets endpoint.Targets, preference, resource string) endpoint.Targets {
+	if targets := getTargetsFromTargetAnnotation(annotations); len(targets) > 0 {
+		return targets
+	}
+	if targets := targetsFromLoadBalancerStatus(status, preference, resource); len(targets) > 0 {
+		return targets
+	}
+	return proxyTargets
+}
+
 func (sc *kongTCPIngressSource) AddEventHandler(ctx context.Context, handler func()) {
 	log.Debug("Adding event handler for TCPIngress")
 
 	// Right now there is no way to remove event handler from informer, see:
 	// https://github.com/kubernetes/kubernetes/issues/79610
 	sc.kongTCPIngressInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	sc.kongProxy.addEventHandler(handler)
 }
 
 // newUnstructuredConverter returns a new unstructuredConverter initialized
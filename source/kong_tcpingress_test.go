@@ -22,6 +22,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
@@ -241,7 +242,7 @@ func TestKongTCPIngressEndpoints(t *testing.T) {
 			_, err = fakeDynamicClient.Resource(kongGroupdVersionResource).Namespace(defaultKongNamespace).Create(context.Background(), &tcpi, metav1.CreateOptions{})
 			assert.NoError(t, err)
 
-			source, err := NewKongTCPIngressSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultKongNamespace, "kubernetes.io/ingress.class=kong")
+			source, err := NewKongTCPIngressSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultKongNamespace, "kubernetes.io/ingress.class=kong", "", LoadBalancerTargetBoth)
 			assert.NoError(t, err)
 			assert.NotNil(t, source)
 
@@ -257,3 +258,88 @@ func TestKongTCPIngressEndpoints(t *testing.T) {
 		})
 	}
 }
+
+func TestKongTCPIngressTargetsAndSharedHosts(t *testing.T) {
+	t.Parallel()
+
+	proxyService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kong-proxy",
+			Namespace: defaultKongNamespace,
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			},
+		},
+	}
+
+	for _, ti := range []struct {
+		title       string
+		annotations map[string]string
+		expected    []*endpoint.Endpoint
+	}{
+		{
+			title: "targets of the Kong proxy Service",
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("db.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			},
+		},
+		{
+			title:       "target annotation overrides the Kong proxy Service",
+			annotations: map[string]string{targetAnnotationKey: "5.6.7.8"},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("db.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+			},
+		},
+	} {
+		ti := ti
+		t.Run(ti.title, func(t *testing.T) {
+			t.Parallel()
+
+			tcpIngress := TCPIngress{
+				TypeMeta: metav1.TypeMeta{
+					APIVersion: kongGroupdVersionResource.GroupVersion().String(),
+					Kind:       "TCPIngress",
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Name:        "db",
+					Namespace:   defaultKongNamespace,
+					Annotations: ti.annotations,
+				},
+				Spec: tcpIngressSpec{
+					Rules: []tcpIngressRule{
+						{Port: 5432, Host: "db.example.com"},
+						{Port: 5433, Host: "db.example.com"},
+					},
+				},
+			}
+
+			scheme := runtime.NewScheme()
+			scheme.AddKnownTypes(kongGroupdVersionResource.GroupVersion(), &TCPIngress{}, &TCPIngressList{})
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClient(scheme)
+
+			tcpi := unstructured.Unstructured{}
+			tcpIngressAsJSON, err := json.Marshal(tcpIngress)
+			require.NoError(t, err)
+			require.NoError(t, tcpi.UnmarshalJSON(tcpIngressAsJSON))
+			_, err = fakeDynamicClient.Resource(kongGroupdVersionResource).Namespace(defaultKongNamespace).Create(context.Background(), &tcpi, metav1.CreateOptions{})
+			require.NoError(t, err)
+
+			source, err := NewKongTCPIngressSource(context.TODO(), fakeDynamicClient, fakeKube.NewSimpleClientset(proxyService), defaultKongNamespace, "", "kong/kong-proxy", LoadBalancerTargetBoth)
+			require.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, ti.expected)
+		})
+	}
+}
+
+func TestNewKongTCPIngressSourceInvalidProxyService(t *testing.T) {
+	t.Parallel()
+
+	fakeDynamicClient := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	_, err := NewKongTCPIngressSource(context.TODO(), fakeDynamicClient, fakeKube.NewSimpleClientset(), "", "", "kong-proxy", LoadBalancerTargetBoth)
+	assert.Error(t, err)
+}
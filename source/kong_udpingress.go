@@ -0,0 +1,182 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+var kongUDPIngressGVR = schema.GroupVersionResource{
+	Group:    "configuration.konghq.com",
+	Version:  "v1beta1",
+	Resource: "udpingresses",
+}
+
+// kongUDPIngressSource is an implementation of Source for Kong UDPIngress objects. The rules of a
+// UDPIngress only match on ports, so the hostnames are taken from the hostname annotation.
+type kongUDPIngressSource struct {
+	annotationFilter       string
+	kongUDPIngressInformer informers.GenericInformer
+	namespace              string
+	kongProxy              *proxyService
+	preferLBTarget         string
+}
+
+// NewKongUDPIngressSource creates a new kongUDPIngressSource with the given config. kongProxyService
+// is the namespace/name of the Service exposing the Kong proxy, used for UDPIngresses without targets.
+// preferLBTarget is the LoadBalancerTarget preference for the load balancer addresses.
+func NewKongUDPIngressSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string, kongProxyService string, preferLBTarget string) (Source, error) {
+	kongProxy, err := newProxyService(ctx, kubeClient, "Kong proxy", kongProxyService, preferLBTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use shared informer to listen for add/update/delete of UDPIngresses in the specified namespace.
+	// Set resync period to 0, to prevent processing when nothing has changed.
+	informerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicKubeClient, 0, namespace, nil)
+	kongUDPIngressInformer := informerFactory.ForResource(kongUDPIngressGVR)
+
+	// Add default resource event handlers to properly initialize informer.
+	kongUDPIngressInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+			},
+		},
+	)
+
+	informerFactory.Start(ctx.Done())
+
+	// wait for the local cache to be populated.
+	if err := waitForDynamicCacheSync(context.Background(), informerFactory); err != nil {
+		return nil, err
+	}
+
+	return &kongUDPIngressSource{
+		annotationFilter:       annotationFilter,
+		kongUDPIngressInformer: kongUDPIngressInformer,
+		namespace:              namespace,
+		kongProxy:              kongProxy,
+		preferLBTarget:         preferLBTarget,
+	}, nil
+}
+
+// Endpoints returns endpoint objects for each host-target combination that should be processed.
+// Retrieves all UDPIngresses in the source's namespace(s).
+func (sc *kongUDPIngressSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	uis, err := sc.kongUDPIngressInformer.Lister().ByNamespace(sc.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := metav1.ParseToLabelSelector(sc.annotationFilter)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	proxyTargets := sc.kongProxy.targets()
+
+	var endpoints []*endpoint.Endpoint
+	for _, udpIngressObj := range uis {
+		unstructuredIngress, ok := udpIngressObj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, errors.New("could not convert")
+		}
+
+		udpIngress := &UDPIngress{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(unstructuredIngress.Object, udpIngress); err != nil {
+			return nil, err
+		}
+
+		// include the UDPIngress if its annotations match the selector
+		if !selector.Empty() && !selector.Matches(labels.Set(udpIngress.Annotations)) {
+			continue
+		}
+
+		fullname := fmt.Sprintf("%s/%s", udpIngress.Namespace, udpIngress.Name)
+
+		hostnames := getHostnamesFromAnnotations(udpIngress.Annotations)
+		if len(hostnames) == 0 {
+			// UDPIngresses are port based, most of them have nothing to publish.
+			continue
+		}
+
+		ttl, err := getTTLFromAnnotations(udpIngress.Annotations)
+		if err != nil {
+			log.Warnf("Ignoring the TTL annotation of UDPIngress %s: %v", fullname, err)
+		}
+		providerSpecific, setIdentifier := getProviderSpecificAnnotations(udpIngress.Annotations)
+		targets := kongTargets(udpIngress.Annotations, udpIngress.Status.LoadBalancer, proxyTargets, sc.preferLBTarget, "udpingress "+fullname)
+
+		var ingressEndpoints []*endpoint.Endpoint
+		for _, hostname := range hostnames {
+			ingressEndpoints = append(ingressEndpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier)...)
+		}
+		if len(ingressEndpoints) == 0 {
+			log.Debugf("No endpoints could be generated from UDPIngress %s", fullname)
+			continue
+		}
+
+		log.Debugf("Endpoints generated from UDPIngress: %s: %v", fullname, ingressEndpoints)
+		for _, ep := range ingressEndpoints {
+			ep.Labels[endpoint.ResourceLabelKey] = fmt.Sprintf("udpingress/%s", fullname)
+		}
+		endpoints = append(endpoints, ingressEndpoints...)
+	}
+
+	for _, ep := range endpoints {
+		sort.Sort(ep.Targets)
+	}
+
+	return endpoints, nil
+}
+
+func (sc *kongUDPIngressSource) AddEventHandler(ctx context.Context, handler func()) {
+	log.Debug("Adding event handler for UDPIngress")
+
+	sc.kongUDPIngressInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	sc.kongProxy.addEventHandler(handler)
+}
+
+// UDPIngress holds the fields of a Kong UDPIngress used by the source, see
+// https://github.com/Kong/kubernetes-ingress-controller/blob/v1.3.0/pkg/apis/configuration/v1beta1/udpingress_types.go
+type UDPIngress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Status tcpIngressStatus `json:"status,omitempty"`
+}
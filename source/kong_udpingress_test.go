@@ -0,0 +1,184 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// This is a compile-time validation that kongUDPIngressSource is a Source.
+var _ Source = &kongUDPIngressSource{}
+
+func newKongUDPIngress(name string, annotations map[string]string, loadBalancer ...interface{}) *unstructured.Unstructured {
+	udpIngress := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": kongUDPIngressGVR.GroupVersion().String(),
+			"kind":       "UDPIngress",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": defaultKongNamespace,
+			},
+			"spec": map[string]interface{}{
+				"rules": []interface{}{
+					map[string]interface{}{
+						"port": int64(9999),
+						"backend": map[string]interface{}{
+							"serviceName": "dns",
+							"servicePort": int64(53),
+						},
+					},
+				},
+			},
+			"status": map[string]interface{}{
+				"loadBalancer": map[string]interface{}{
+					"ingress": loadBalancer,
+				},
+			},
+		},
+	}
+	udpIngress.SetAnnotations(annotations)
+	return udpIngress
+}
+
+func TestKongUDPIngressEndpoints(t *testing.T) {
+	t.Parallel()
+
+	proxyService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kong-proxy",
+			Namespace: defaultKongNamespace,
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{Hostname: "proxy.example.net"}},
+			},
+		},
+	}
+
+	for _, ti := range []struct {
+		title            string
+		kongProxyService string
+		udpIngresses     []*unstructured.Unstructured
+		expected         []*endpoint.Endpoint
+	}{
+		{
+			title: "hostname annotation with load balancer status",
+			udpIngresses: []*unstructured.Unstructured{
+				newKongUDPIngress("dns", map[string]string{
+					hostnameAnnotationKey: "dns.example.com",
+					ttlAnnotationKey:      "60",
+				}, map[string]interface{}{"ip": "1.2.3.4"}),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpointWithTTL("dns.example.com", endpoint.RecordTypeA, 60, "1.2.3.4"),
+			},
+		},
+		{
+			title:            "targets of the Kong proxy Service",
+			kongProxyService: "kong/kong-proxy",
+			udpIngresses: []*unstructured.Unstructured{
+				newKongUDPIngress("dns", map[string]string{
+					hostnameAnnotationKey: "dns.example.com",
+				}),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("dns.example.com", endpoint.RecordTypeCNAME, "proxy.example.net"),
+			},
+		},
+		{
+			title:            "target annotation overrides the load balancer status",
+			kongProxyService: "kong/kong-proxy",
+			udpIngresses: []*unstructured.Unstructured{
+				newKongUDPIngress("dns", map[string]string{
+					hostnameAnnotationKey: "dns.example.com",
+					targetAnnotationKey:   "5.6.7.8",
+				}, map[string]interface{}{"ip": "1.2.3.4"}),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("dns.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+			},
+		},
+		{
+			title: "invalid TTL annotation is ignored",
+			udpIngresses: []*unstructured.Unstructured{
+				newKongUDPIngress("dns", map[string]string{
+					hostnameAnnotationKey: "dns.example.com",
+					ttlAnnotationKey:      "forever",
+				}, map[string]interface{}{"ip": "1.2.3.4"}),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("dns.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			},
+		},
+		{
+			title:            "missing Kong proxy Service has no targets",
+			kongProxyService: "kong/missing",
+			udpIngresses: []*unstructured.Unstructured{
+				newKongUDPIngress("dns", map[string]string{
+					hostnameAnnotationKey: "dns.example.com",
+				}, map[string]interface{}{"ip": "1.2.3.4"}),
+				newKongUDPIngress("syslog", map[string]string{
+					hostnameAnnotationKey: "syslog.example.com",
+				}),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("dns.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+			},
+		},
+		{
+			title:            "UDPIngress without hostname annotation is skipped",
+			kongProxyService: "kong/kong-proxy",
+			udpIngresses: []*unstructured.Unstructured{
+				newKongUDPIngress("syslog", nil, map[string]interface{}{"ip": "1.2.3.4"}),
+			},
+			expected: []*endpoint.Endpoint{},
+		},
+	} {
+		ti := ti
+		t.Run(ti.title, func(t *testing.T) {
+			t.Parallel()
+
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{
+					kongUDPIngressGVR: "UDPIngressList",
+				})
+			for _, udpIngress := range ti.udpIngresses {
+				_, err := fakeDynamicClient.Resource(kongUDPIngressGVR).Namespace(defaultKongNamespace).Create(context.Background(), udpIngress, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			source, err := NewKongUDPIngressSource(context.TODO(), fakeDynamicClient, fakeKube.NewSimpleClientset(proxyService), defaultKongNamespace, "", ti.kongProxyService, LoadBalancerTargetBoth)
+			require.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, ti.expected)
+		})
+	}
+}
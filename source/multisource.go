@@ -18,6 +18,9 @@ package source
 
 import (
 	"context"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
 
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -45,7 +48,55 @@ func (ms *multiSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, err
 		result = append(result, endpoints...)
 	}
 
-	return result, nil
+	return dropCNAMELoops(result), nil
+}
+
+// dropCNAMELoops removes the CNAME endpoints which lead back to themselves, directly or through other
+// CNAME endpoints of the given ones, as publishing them would create a CNAME loop. The endpoints of
+// all the sources are checked together, so that loops spanning several sources are found too.
+func dropCNAMELoops(endpoints []*endpoint.Endpoint) []*endpoint.Endpoint {
+	normalize := func(name string) string {
+		return strings.ToLower(strings.TrimSuffix(name, "."))
+	}
+	cnameTargets := map[string][]string{}
+	for _, ep := range endpoints {
+		if ep.RecordType != endpoint.RecordTypeCNAME {
+			continue
+		}
+		name := normalize(ep.DNSName)
+		for _, target := range ep.Targets {
+			cnameTargets[name] = append(cnameTargets[name], normalize(target))
+		}
+	}
+
+	// loops returns true if name can be reached from its own CNAME targets.
+	loops := func(name string) bool {
+		visited := map[string]bool{}
+		pending := append([]string{}, cnameTargets[name]...)
+		for len(pending) > 0 {
+			current := pending[len(pending)-1]
+			pending = pending[:len(pending)-1]
+			if current == name {
+				return true
+			}
+			if visited[current] {
+				continue
+			}
+			visited[current] = true
+			pending = append(pending, cnameTargets[current]...)
+		}
+		return false
+	}
+
+	result := make([]*endpoint.Endpoint, 0, len(endpoints))
+	for _, ep := range endpoints {
+		if ep.RecordType == endpoint.RecordTypeCNAME && loops(normalize(ep.DNSName)) {
+			log.Warnf("Skipping the CNAME endpoint %s -> %v of %s, it is part of a CNAME loop", ep.DNSName, ep.Targets, ep.Labels[endpoint.ResourceLabelKey])
+			continue
+		}
+		result = append(result, ep)
+	}
+	return result
 }
 
 func (ms *multiSource) AddEventHandler(ctx context.Context, handler func()) {
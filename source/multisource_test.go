@@ -35,6 +35,7 @@ func TestMultiSource(t *testing.T) {
 	t.Run("Endpoints", testMultiSourceEndpoints)
 	t.Run("EndpointsWithError", testMultiSourceEndpointsWithError)
 	t.Run("EndpointsDefaultTargets", testMultiSourceEndpointsDefaultTargets)
+	t.Run("EndpointsCNAMELoops", testMultiSourceEndpointsCNAMELoops)
 }
 
 // testMultiSourceImplementsSource tests that multiSource is a valid Source.
@@ -159,3 +160,30 @@ func testMultiSourceEndpointsDefaultTargets(t *testing.T) {
 	// Validate that the nested sources were called.
 	src.AssertExpectations(t)
 }
+
+// testMultiSourceEndpointsCNAMELoops tests that CNAME endpoints leading back to themselves are dropped,
+// also when the loop spans several sources.
+func testMultiSourceEndpointsCNAMELoops(t *testing.T) {
+	// The loop a -> b -> a spans both sources, c only leads into it and e loops on itself.
+	first := new(testutils.MockSource)
+	first.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("a.example.org", endpoint.RecordTypeCNAME, "b.example.org"),
+		endpoint.NewEndpoint("c.example.org", endpoint.RecordTypeCNAME, "a.example.org"),
+		endpoint.NewEndpoint("d.example.org", endpoint.RecordTypeCNAME, "remote.example.com"),
+	}, nil)
+	second := new(testutils.MockSource)
+	second.On("Endpoints").Return([]*endpoint.Endpoint{
+		endpoint.NewEndpoint("B.example.org.", endpoint.RecordTypeCNAME, "a.example.org."),
+		endpoint.NewEndpoint("e.example.org", endpoint.RecordTypeCNAME, "e.example.org"),
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	}, nil)
+
+	endpoints, err := NewMultiSource([]Source{first, second}, nil).Endpoints(context.Background())
+	require.NoError(t, err)
+
+	validateEndpoints(t, endpoints, []*endpoint.Endpoint{
+		endpoint.NewEndpoint("c.example.org", endpoint.RecordTypeCNAME, "a.example.org"),
+		endpoint.NewEndpoint("d.example.org", endpoint.RecordTypeCNAME, "remote.example.com"),
+		endpoint.NewEndpoint("b.example.org", endpoint.RecordTypeA, "1.2.3.4"),
+	})
+}
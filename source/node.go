@@ -19,6 +19,7 @@ package source
 import (
 	"context"
 	"fmt"
+	"net"
 	"text/template"
 
 	log "github.com/sirupsen/logrus"
@@ -34,14 +35,24 @@ import (
 )
 
 type nodeSource struct {
-	client           kubernetes.Interface
-	annotationFilter string
-	fqdnTemplate     *template.Template
-	nodeInformer     coreinformers.NodeInformer
+	client               kubernetes.Interface
+	annotationFilter     string
+	fqdnTemplate         *template.Template
+	nodeInformer         coreinformers.NodeInformer
+	labelSelector        labels.Selector
+	preferredAddressType v1.NodeAddressType
 }
 
-// NewNodeSource creates a new nodeSource with the given config.
-func NewNodeSource(ctx context.Context, kubeClient kubernetes.Interface, annotationFilter, fqdnTemplate string) (Source, error) {
+// nodeEndpointKey identifies the endpoint of a DNS name and record type, as several nodes
+// may be published with the same DNS name.
+type nodeEndpointKey struct {
+	dnsName    string
+	recordType string
+}
+
+// NewNodeSource creates a new nodeSource with the given config. Nodes are published with
+// their addresses of the preferred type, ExternalIP or InternalIP, falling back to the other.
+func NewNodeSource(ctx context.Context, kubeClient kubernetes.Interface, annotationFilter, fqdnTemplate string, labelSelector labels.Selector, preferredAddressType v1.NodeAddressType) (Source, error) {
 	tmpl, err := parseTemplate(fqdnTemplate)
 	if err != nil {
 		return nil, err
@@ -68,17 +79,26 @@ func NewNodeSource(ctx context.Context, kubeClient kubernetes.Interface, annotat
 		return nil, err
 	}
 
+	if preferredAddressType == "" {
+		preferredAddressType = v1.NodeExternalIP
+	}
+	if labelSelector == nil {
+		labelSelector = labels.Everything()
+	}
+
 	return &nodeSource{
-		client:           kubeClient,
-		annotationFilter: annotationFilter,
-		fqdnTemplate:     tmpl,
-		nodeInformer:     nodeInformer,
+		client:               kubeClient,
+		annotationFilter:     annotationFilter,
+		fqdnTemplate:         tmpl,
+		nodeInformer:         nodeInformer,
+		labelSelector:        labelSelector,
+		preferredAddressType: preferredAddressType,
 	}, nil
 }
 
 // Endpoints returns endpoint objects for each service that should be processed.
 func (ns *nodeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
-	nodes, err := ns.nodeInformer.Lister().List(labels.Everything())
+	nodes, err := ns.nodeInformer.Lister().List(ns.labelSelector)
 	if err != nil {
 		return nil, err
 	}
@@ -88,7 +108,7 @@ func (ns *nodeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 		return nil, err
 	}
 
-	endpoints := map[string]*endpoint.Endpoint{}
+	endpoints := map[nodeEndpointKey]*endpoint.Endpoint{}
 
 	// create endpoints for all nodes
 	for _, node := range nodes {
@@ -104,28 +124,21 @@ func (ns *nodeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 
 		ttl, err := getTTLFromAnnotations(node.Annotations)
 		if err != nil {
-			log.Warn(err)
-		}
-
-		// create new endpoint with the information we already have
-		ep := &endpoint.Endpoint{
-			RecordType: "A", // hardcoded DNS record type
-			RecordTTL:  ttl,
+			log.Warnf("Ignoring the TTL annotation of node %s: %v", node.Name, err)
 		}
 
+		var dnsName string
 		if ns.fqdnTemplate != nil {
 			hostnames, err := execTemplate(ns.fqdnTemplate, node)
 			if err != nil {
 				return nil, err
 			}
-			hostname := ""
 			if len(hostnames) > 0 {
-				hostname = hostnames[0]
+				dnsName = hostnames[0]
 			}
-			ep.DNSName = hostname
-			log.Debugf("applied template for %s, converting to %s", node.Name, ep.DNSName)
+			log.Debugf("applied template for %s, converting to %s", node.Name, dnsName)
 		} else {
-			ep.DNSName = node.Name
+			dnsName = node.Name
 			log.Debugf("not applying template for %s", node.Name)
 		}
 
@@ -134,14 +147,32 @@ func (ns *nodeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 			return nil, fmt.Errorf("failed to get node address from %s: %s", node.Name, err.Error())
 		}
 
-		ep.Targets = endpoint.Targets(addrs)
-		ep.Labels = endpoint.NewLabels()
+		// IPv4 and IPv6 addresses are published as separate A and AAAA records
+		targetsByType := map[string]endpoint.Targets{}
+		for _, addr := range addrs {
+			recordType := endpoint.RecordTypeA
+			if ip := net.ParseIP(addr); ip != nil && ip.To4() == nil {
+				recordType = endpoint.RecordTypeAAAA
+			}
+			targetsByType[recordType] = append(targetsByType[recordType], addr)
+		}
 
-		log.Debugf("adding endpoint %s", ep)
-		if _, ok := endpoints[ep.DNSName]; ok {
-			endpoints[ep.DNSName].Targets = append(endpoints[ep.DNSName].Targets, ep.Targets...)
-		} else {
-			endpoints[ep.DNSName] = ep
+		for recordType, targets := range targetsByType {
+			ep := &endpoint.Endpoint{
+				DNSName:    dnsName,
+				RecordType: recordType,
+				RecordTTL:  ttl,
+				Targets:    targets,
+				Labels:     endpoint.NewLabels(),
+			}
+
+			log.Debugf("adding endpoint %s", ep)
+			key := nodeEndpointKey{dnsName: ep.DNSName, recordType: ep.RecordType}
+			if _, ok := endpoints[key]; ok {
+				endpoints[key].Targets = append(endpoints[key].Targets, ep.Targets...)
+			} else {
+				endpoints[key] = ep
+			}
 		}
 	}
 
@@ -156,8 +187,8 @@ func (ns *nodeSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, erro
 func (ns *nodeSource) AddEventHandler(ctx context.Context, handler func()) {
 }
 
-// nodeAddress returns node's externalIP and if that's not found, node's internalIP
-// basically what k8s.io/kubernetes/pkg/util/node.GetPreferredNodeAddress does
+// nodeAddresses returns node's addresses of the preferred type and if none are found, the
+// addresses of the other type, basically what k8s.io/kubernetes/pkg/util/node.GetPreferredNodeAddress does
 func (ns *nodeSource) nodeAddresses(node *v1.Node) ([]string, error) {
 	addresses := map[v1.NodeAddressType][]string{
 		v1.NodeExternalIP: {},
@@ -168,12 +199,15 @@ func (ns *nodeSource) nodeAddresses(node *v1.Node) ([]string, error) {
 		addresses[addr.Type] = append(addresses[addr.Type], addr.Address)
 	}
 
-	if len(addresses[v1.NodeExternalIP]) > 0 {
-		return addresses[v1.NodeExternalIP], nil
+	addressTypes := []v1.NodeAddressType{v1.NodeExternalIP, v1.NodeInternalIP}
+	if ns.preferredAddressType == v1.NodeInternalIP {
+		addressTypes = []v1.NodeAddressType{v1.NodeInternalIP, v1.NodeExternalIP}
 	}
 
-	if len(addresses[v1.NodeInternalIP]) > 0 {
-		return addresses[v1.NodeInternalIP], nil
+	for _, addressType := range addressTypes {
+		if len(addresses[addressType]) > 0 {
+			return addresses[addressType], nil
+		}
 	}
 
 	return nil, fmt.Errorf("could not find node address for %s", node.Name)
@@ -24,6 +24,7 @@ import (
 	"github.com/stretchr/testify/require"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/fake"
 
 	"sigs.k8s.io/external-dns/endpoint"
@@ -34,6 +35,7 @@ func TestNodeSource(t *testing.T) {
 
 	t.Run("NewNodeSource", testNodeSourceNewNodeSource)
 	t.Run("Endpoints", testNodeSourceEndpoints)
+	t.Run("EndpointsDualStack", testNodeSourceEndpointsDualStack)
 }
 
 // testNodeSourceNewNodeSource tests that NewNodeService doesn't return an error.
@@ -75,6 +77,8 @@ func testNodeSourceNewNodeSource(t *testing.T) {
 				fake.NewSimpleClientset(),
 				ti.annotationFilter,
 				ti.fqdnTemplate,
+				labels.Everything(),
+				v1.NodeExternalIP,
 			)
 
 			if ti.expectError {
@@ -358,6 +362,8 @@ func testNodeSourceEndpoints(t *testing.T) {
 				kubernetes,
 				tc.annotationFilter,
 				tc.fqdnTemplate,
+				labels.Everything(),
+				v1.NodeExternalIP,
 			)
 			require.NoError(t, err)
 
@@ -373,3 +379,93 @@ func testNodeSourceEndpoints(t *testing.T) {
 		})
 	}
 }
+
+// testNodeSourceEndpointsDualStack tests the address family, address preference and label selector handling.
+func testNodeSourceEndpointsDualStack(t *testing.T) {
+	t.Parallel()
+
+	for _, tc := range []struct {
+		title                string
+		labelSelector        string
+		preferredAddressType v1.NodeAddressType
+		nodes                []*v1.Node
+		expected             []*endpoint.Endpoint
+	}{
+		{
+			title: "node with IPv4 and IPv6 addresses returns A and AAAA endpoints",
+			nodes: []*v1.Node{
+				newTestNode("node1", nil, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "1.2.3.4"}, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "2001:db8::1"}),
+			},
+			expected: []*endpoint.Endpoint{
+				{RecordType: endpoint.RecordTypeA, DNSName: "node1", Targets: endpoint.Targets{"1.2.3.4"}},
+				{RecordType: endpoint.RecordTypeAAAA, DNSName: "node1", Targets: endpoint.Targets{"2001:db8::1"}},
+			},
+		},
+		{
+			title: "node with only IPv6 addresses returns AAAA endpoint",
+			nodes: []*v1.Node{
+				newTestNode("node1", nil, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "2001:db8::1"}),
+			},
+			expected: []*endpoint.Endpoint{
+				{RecordType: endpoint.RecordTypeAAAA, DNSName: "node1", Targets: endpoint.Targets{"2001:db8::1"}},
+			},
+		},
+		{
+			title:                "internal addresses are preferred",
+			preferredAddressType: v1.NodeInternalIP,
+			nodes: []*v1.Node{
+				newTestNode("node1", nil, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "1.2.3.4"}, v1.NodeAddress{Type: v1.NodeInternalIP, Address: "10.0.0.1"}),
+				newTestNode("node2", nil, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "1.2.3.5"}),
+			},
+			expected: []*endpoint.Endpoint{
+				{RecordType: endpoint.RecordTypeA, DNSName: "node1", Targets: endpoint.Targets{"10.0.0.1"}},
+				{RecordType: endpoint.RecordTypeA, DNSName: "node2", Targets: endpoint.Targets{"1.2.3.5"}},
+			},
+		},
+		{
+			title:         "only nodes matching the label selector are returned",
+			labelSelector: "node-role.kubernetes.io/worker",
+			nodes: []*v1.Node{
+				newTestNode("worker", map[string]string{"node-role.kubernetes.io/worker": ""}, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "1.2.3.4"}),
+				newTestNode("control-plane", map[string]string{"node-role.kubernetes.io/control-plane": ""}, v1.NodeAddress{Type: v1.NodeExternalIP, Address: "1.2.3.5"}),
+			},
+			expected: []*endpoint.Endpoint{
+				{RecordType: endpoint.RecordTypeA, DNSName: "worker", Targets: endpoint.Targets{"1.2.3.4"}},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			t.Parallel()
+
+			kubernetes := fake.NewSimpleClientset()
+			for _, node := range tc.nodes {
+				_, err := kubernetes.CoreV1().Nodes().Create(context.Background(), node, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			labelSelector, err := labels.Parse(tc.labelSelector)
+			require.NoError(t, err)
+
+			client, err := NewNodeSource(context.TODO(), kubernetes, "", "", labelSelector, tc.preferredAddressType)
+			require.NoError(t, err)
+
+			endpoints, err := client.Endpoints(context.Background())
+			require.NoError(t, err)
+
+			validateEndpoints(t, endpoints, tc.expected)
+		})
+	}
+}
+
+func newTestNode(name string, nodeLabels map[string]string, addresses ...v1.NodeAddress) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:   name,
+			Labels: nodeLabels,
+		},
+		Status: v1.NodeStatus{
+			Addresses: addresses,
+		},
+	}
+}
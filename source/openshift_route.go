@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"sort"
+	"strings"
 	"text/template"
 
 	routev1 "github.com/openshift/api/route/v1"
@@ -176,7 +177,7 @@ func (ors *ocpRouteSource) endpointsFromTemplate(ocpRoute *routev1.Route) ([]*en
 
 	ttl, err := getTTLFromAnnotations(ocpRoute.Annotations)
 	if err != nil {
-		log.Warn(err)
+		log.Warnf("Ignoring the TTL annotation of OpenShift Route %s/%s: %v", ocpRoute.Namespace, ocpRoute.Name, err)
 	}
 
 	targets := getTargetsFromTargetAnnotation(ocpRoute.Annotations)
@@ -236,7 +237,7 @@ func (ors *ocpRouteSource) endpointsFromOcpRoute(ocpRoute *routev1.Route, ignore
 
 	ttl, err := getTTLFromAnnotations(ocpRoute.Annotations)
 	if err != nil {
-		log.Warn(err)
+		log.Warnf("Ignoring the TTL annotation of OpenShift Route %s/%s: %v", ocpRoute.Namespace, ocpRoute.Name, err)
 	}
 
 	targets := getTargetsFromTargetAnnotation(ocpRoute.Annotations)
@@ -248,6 +249,13 @@ func (ors *ocpRouteSource) endpointsFromOcpRoute(ocpRoute *routev1.Route, ignore
 
 	providerSpecific, setIdentifier := getProviderSpecificAnnotations(ocpRoute.Annotations)
 
+	// a route with the Subdomain wildcard policy serves all hosts of the domain of its host
+	if host != "" && ocpRoute.Spec.WildcardPolicy == routev1.WildcardPolicySubdomain {
+		if i := strings.Index(host, "."); i > 0 {
+			host = "*" + host[i:]
+		}
+	}
+
 	if host != "" {
 		endpoints = append(endpoints, endpointsForHostname(host, targets, ttl, providerSpecific, setIdentifier)...)
 	}
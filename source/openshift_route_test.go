@@ -204,6 +204,42 @@ func testOcpRouteSourceEndpoints(t *testing.T) {
 				},
 			},
 		},
+		{
+			title: "route with subdomain wildcard policy",
+			ocpRoute: &routev1.Route{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: "default",
+					Name:      "route-with-wildcard",
+				},
+				Spec: routev1.RouteSpec{
+					Host:           "wildcard.my-domain.com",
+					WildcardPolicy: routev1.WildcardPolicySubdomain,
+				},
+				Status: routev1.RouteStatus{
+					Ingress: []routev1.RouteIngress{
+						{
+							Host:                    "wildcard.my-domain.com",
+							RouterCanonicalHostname: "apps.my-domain.com",
+							WildcardPolicy:          routev1.WildcardPolicySubdomain,
+							Conditions: []routev1.RouteIngressCondition{
+								{
+									Type:   routev1.RouteAdmitted,
+									Status: corev1.ConditionTrue,
+								},
+							},
+						},
+					},
+				},
+			},
+			expected: []*endpoint.Endpoint{
+				{
+					DNSName: "*.my-domain.com",
+					Targets: []string{
+						"apps.my-domain.com",
+					},
+				},
+			},
+		},
 		{
 			title: "route with basic hostname, route status target and ocpRouterName defined",
 			ocpRoute: &routev1.Route{
@@ -18,6 +18,8 @@ package source
 
 import (
 	"context"
+	"text/template"
+	"time"
 
 	"sigs.k8s.io/external-dns/endpoint"
 
@@ -36,10 +38,23 @@ type podSource struct {
 	podInformer   coreinformers.PodInformer
 	nodeInformer  coreinformers.NodeInformer
 	compatibility string
+	fqdnTemplate  *template.Template
+	labelSelector labels.Selector
+	templateTTL   endpoint.TTL
 }
 
-// NewPodSource creates a new podSource with the given config.
-func NewPodSource(ctx context.Context, kubeClient kubernetes.Interface, namespace string, compatibility string) (Source, error) {
+// NewPodSource creates a new podSource with the given config. If fqdnTemplate is set, every
+// ready hostNetwork pod matching labelSelector is also published with its host IP under the
+// templated hostnames using templateTTL.
+func NewPodSource(ctx context.Context, kubeClient kubernetes.Interface, namespace string, compatibility string, fqdnTemplate string, labelSelector labels.Selector, templateTTL time.Duration) (Source, error) {
+	tmpl, err := parseTemplate(fqdnTemplate)
+	if err != nil {
+		return nil, err
+	}
+	if labelSelector == nil {
+		labelSelector = labels.Everything()
+	}
+
 	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
 	podInformer := informerFactory.Core().V1().Pods()
 	nodeInformer := informerFactory.Core().V1().Nodes()
@@ -70,6 +85,9 @@ func NewPodSource(ctx context.Context, kubeClient kubernetes.Interface, namespac
 		nodeInformer:  nodeInformer,
 		namespace:     namespace,
 		compatibility: compatibility,
+		fqdnTemplate:  tmpl,
+		labelSelector: labelSelector,
+		templateTTL:   endpoint.TTL(templateTTL.Seconds()),
 	}, nil
 }
 
@@ -83,12 +101,34 @@ func (ps *podSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error
 	}
 
 	domains := make(map[string][]string)
+	templateHostIPs := make(map[string]map[string]bool)
 	for _, pod := range pods {
 		if !pod.Spec.HostNetwork {
 			log.Debugf("skipping pod %s. hostNetwork=false", pod.Name)
 			continue
 		}
 
+		if ps.fqdnTemplate != nil && ps.labelSelector.Matches(labels.Set(pod.Labels)) {
+			if isPodReady(pod) && pod.Status.HostIP != "" {
+				hostnames, err := execTemplate(ps.fqdnTemplate, pod)
+				if err != nil {
+					return nil, err
+				}
+				for _, domain := range hostnames {
+					if templateHostIPs[domain] == nil {
+						templateHostIPs[domain] = make(map[string]bool)
+					}
+					// hostNetwork pods on the same node share its host IP, publish it only once.
+					if !templateHostIPs[domain][pod.Status.HostIP] {
+						domains[domain] = append(domains[domain], pod.Status.HostIP)
+						templateHostIPs[domain][pod.Status.HostIP] = true
+					}
+				}
+			} else {
+				log.Debugf("skipping template for pod %s. ready=false", pod.Name)
+			}
+		}
+
 		if domain, ok := pod.Annotations[internalHostnameAnnotationKey]; ok {
 			if _, ok := domains[domain]; !ok {
 				domains[domain] = []string{}
@@ -133,7 +173,21 @@ func (ps *podSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error
 	}
 	endpoints := []*endpoint.Endpoint{}
 	for domain, targets := range domains {
-		endpoints = append(endpoints, endpoint.NewEndpoint(domain, endpoint.RecordTypeA, targets...))
+		if _, ok := templateHostIPs[domain]; ok {
+			endpoints = append(endpoints, endpointsForHostname(domain, targets, ps.templateTTL, nil, "")...)
+		} else {
+			endpoints = append(endpoints, endpoint.NewEndpoint(domain, endpoint.RecordTypeA, targets...))
+		}
 	}
 	return endpoints, nil
 }
+
+// isPodReady returns true if the pod's Ready condition is true.
+func isPodReady(pod *corev1.Pod) bool {
+	for _, condition := range pod.Status.Conditions {
+		if condition.Type == corev1.PodReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
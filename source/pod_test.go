@@ -19,10 +19,12 @@ package source
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/kubernetes/fake"
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -412,7 +414,7 @@ func TestPodSource(t *testing.T) {
 				}
 			}
 
-			client, err := NewPodSource(context.TODO(), kubernetes, tc.targetNamespace, tc.compatibility)
+			client, err := NewPodSource(context.TODO(), kubernetes, tc.targetNamespace, tc.compatibility, "", labels.Everything(), time.Minute)
 			require.NoError(t, err)
 
 			endpoints, err := client.Endpoints(ctx)
@@ -428,3 +430,123 @@ func TestPodSource(t *testing.T) {
 
 	}
 }
+
+func TestPodSourceFqdnTemplate(t *testing.T) {
+	t.Parallel()
+
+	readyPod := func(name, nodeName, hostIP string, podLabels map[string]string, ready corev1.ConditionStatus) *corev1.Pod {
+		return &corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: "edge",
+				Labels:    podLabels,
+			},
+			Spec: corev1.PodSpec{
+				HostNetwork: true,
+				NodeName:    nodeName,
+			},
+			Status: corev1.PodStatus{
+				HostIP:     hostIP,
+				PodIP:      hostIP,
+				Conditions: []corev1.PodCondition{{Type: corev1.PodReady, Status: ready}},
+			},
+		}
+	}
+
+	for _, tc := range []struct {
+		title         string
+		labelSelector string
+		pods          []*corev1.Pod
+		expected      []*endpoint.Endpoint
+	}{
+		{
+			title: "ready pods are published with their host IP",
+			pods: []*corev1.Pod{
+				readyPod("edge-1", "node1", "54.10.11.1", nil, corev1.ConditionTrue),
+				readyPod("edge-2", "node2", "54.10.11.2", nil, corev1.ConditionTrue),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "edge-node1.example.org", Targets: endpoint.Targets{"54.10.11.1"}, RecordType: endpoint.RecordTypeA, RecordTTL: 30},
+				{DNSName: "edge-node2.example.org", Targets: endpoint.Targets{"54.10.11.2"}, RecordType: endpoint.RecordTypeA, RecordTTL: 30},
+			},
+		},
+		{
+			title: "not ready pods are not published",
+			pods: []*corev1.Pod{
+				readyPod("edge-1", "node1", "54.10.11.1", nil, corev1.ConditionTrue),
+				readyPod("edge-2", "node2", "54.10.11.2", nil, corev1.ConditionFalse),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "edge-node1.example.org", Targets: endpoint.Targets{"54.10.11.1"}, RecordType: endpoint.RecordTypeA, RecordTTL: 30},
+			},
+		},
+		{
+			title:         "only pods matching the label selector are published",
+			labelSelector: "app=edge-proxy",
+			pods: []*corev1.Pod{
+				readyPod("edge-1", "node1", "54.10.11.1", map[string]string{"app": "edge-proxy"}, corev1.ConditionTrue),
+				readyPod("other-1", "node1", "54.10.11.1", map[string]string{"app": "other"}, corev1.ConditionTrue),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "edge-node1.example.org", Targets: endpoint.Targets{"54.10.11.1"}, RecordType: endpoint.RecordTypeA, RecordTTL: 30},
+			},
+		},
+		{
+			title: "pods on the same node publish its host IP once",
+			pods: []*corev1.Pod{
+				readyPod("edge-1", "node1", "54.10.11.1", nil, corev1.ConditionTrue),
+				readyPod("monitoring-1", "node1", "54.10.11.1", nil, corev1.ConditionTrue),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "edge-node1.example.org", Targets: endpoint.Targets{"54.10.11.1"}, RecordType: endpoint.RecordTypeA, RecordTTL: 30},
+			},
+		},
+		{
+			title: "IPv6 host IPs are published as AAAA records",
+			pods: []*corev1.Pod{
+				readyPod("edge-1", "node1", "2001:db8::1", nil, corev1.ConditionTrue),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "edge-node1.example.org", Targets: endpoint.Targets{"2001:db8::1"}, RecordType: endpoint.RecordTypeAAAA, RecordTTL: 30},
+			},
+		},
+		{
+			title:         "the label selector does not apply to annotated pods",
+			labelSelector: "app=edge-proxy",
+			pods: []*corev1.Pod{
+				func() *corev1.Pod {
+					pod := readyPod("other-1", "node1", "54.10.11.1", map[string]string{"app": "other"}, corev1.ConditionTrue)
+					pod.Annotations = map[string]string{internalHostnameAnnotationKey: "internal.example.org"}
+					return pod
+				}(),
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "internal.example.org", Targets: endpoint.Targets{"54.10.11.1"}, RecordType: endpoint.RecordTypeA},
+			},
+		},
+	} {
+		tc := tc
+		t.Run(tc.title, func(t *testing.T) {
+			t.Parallel()
+
+			kubernetes := fake.NewSimpleClientset()
+			ctx := context.Background()
+
+			for _, pod := range tc.pods {
+				_, err := kubernetes.CoreV1().Pods(pod.Namespace).Create(ctx, pod, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			labelSelector, err := labels.Parse(tc.labelSelector)
+			require.NoError(t, err)
+
+			client, err := NewPodSource(ctx, kubernetes, "edge", "", "edge-{{.Spec.NodeName}}.example.org", labelSelector, 30*time.Second)
+			require.NoError(t, err)
+
+			endpoints, err := client.Endpoints(ctx)
+			require.NoError(t, err)
+
+			validateEndpoints(t, endpoints, tc.expected)
+		})
+	}
+}
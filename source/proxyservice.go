@@ -0,0 +1,106 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/client-go/informers"
+	coreinformers "k8s.io/client-go/informers/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// proxyService reads the Service exposing a proxy, e.g. Kong or Traefik, from an informer watching
+// only this Service.
+type proxyService struct {
+	kind            string
+	namespace       string
+	name            string
+	preference      string
+	serviceInformer coreinformers.ServiceInformer
+}
+
+// newProxyService starts an informer for the Service given as namespace/name. kind names the proxy
+// in errors and warnings, preference is the LoadBalancerTarget preference for its addresses. It
+// returns nil if service is empty.
+func newProxyService(ctx context.Context, kubeClient kubernetes.Interface, kind, service, preference string) (*proxyService, error) {
+	if service == "" {
+		return nil, nil
+	}
+	parts := strings.Split(service, "/")
+	if len(parts) != 2 {
+		return nil, fmt.Errorf("invalid %s service %q, expected namespace/name", kind, service)
+	}
+
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0, informers.WithNamespace(parts[0]), informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+		o.FieldSelector = fields.OneTermEqualSelector("metadata.name", parts[1]).String()
+	}))
+	serviceInformer := informerFactory.Core().V1().Services()
+
+	// Add default resource event handlers to properly initialize informer.
+	serviceInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+			},
+		},
+	)
+
+	informerFactory.Start(ctx.Done())
+
+	// wait for the local cache to be populated.
+	if err := waitForCacheSync(context.Background(), informerFactory); err != nil {
+		return nil, err
+	}
+
+	return &proxyService{
+		kind:            kind,
+		namespace:       parts[0],
+		name:            parts[1],
+		preference:      preference,
+		serviceInformer: serviceInformer,
+	}, nil
+}
+
+// targets returns the load balancer addresses of the Service, if configured. A missing Service has
+// no addresses, so that the resources with their own targets are still published.
+func (p *proxyService) targets() endpoint.Targets {
+	if p == nil {
+		return nil
+	}
+	svc, err := p.serviceInformer.Lister().Services(p.namespace).Get(p.name)
+	if err != nil {
+		log.Warnf("Failed to get %s service %s/%s: %v", p.kind, p.namespace, p.name, err)
+		return nil
+	}
+	return targetsFromLoadBalancerStatus(svc.Status.LoadBalancer, p.preference, fmt.Sprintf("service %s/%s", p.namespace, p.name))
+}
+
+// addEventHandler triggers the handler when the Service changes, if configured.
+func (p *proxyService) addEventHandler(handler func()) {
+	if p == nil {
+		return
+	}
+	p.serviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+}
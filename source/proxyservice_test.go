@@ -0,0 +1,80 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+func TestProxyServiceTargetsFromCache(t *testing.T) {
+	t.Parallel()
+
+	proxyService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "kong-proxy",
+			Namespace: defaultKongNamespace,
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{{IP: "1.2.3.4"}},
+			},
+		},
+	}
+	fakeKubernetesClient := fakeKube.NewSimpleClientset(proxyService)
+
+	proxy, err := newProxyService(context.TODO(), fakeKubernetesClient, "Kong proxy", "kong/kong-proxy", LoadBalancerTargetBoth)
+	require.NoError(t, err)
+
+	// The targets are read from the informer cache only.
+	fakeKubernetesClient.ClearActions()
+	assert.Equal(t, endpoint.Targets{"1.2.3.4"}, proxy.targets())
+	assert.Empty(t, fakeKubernetesClient.Actions())
+
+	// Changes are picked up by the informer.
+	proxyService.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{IP: "5.6.7.8"}}
+	_, err = fakeKubernetesClient.CoreV1().Services(defaultKongNamespace).UpdateStatus(context.Background(), proxyService, metav1.UpdateOptions{})
+	require.NoError(t, err)
+	require.Eventually(t, func() bool {
+		targets := proxy.targets()
+		return len(targets) == 1 && targets[0] == "5.6.7.8"
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestProxyServiceTargetsOfMissingService(t *testing.T) {
+	t.Parallel()
+
+	proxy, err := newProxyService(context.TODO(), fakeKube.NewSimpleClientset(), "Traefik", "traefik/traefik", LoadBalancerTargetBoth)
+	require.NoError(t, err)
+	assert.Empty(t, proxy.targets())
+}
+
+func TestNewProxyServiceInvalidService(t *testing.T) {
+	t.Parallel()
+
+	_, err := newProxyService(context.TODO(), fakeKube.NewSimpleClientset(), "Traefik", "traefik", LoadBalancerTargetBoth)
+	assert.EqualError(t, err, `invalid Traefik service "traefik", expected namespace/name`)
+}
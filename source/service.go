@@ -19,6 +19,7 @@ package source
 import (
 	"context"
 	"fmt"
+	"net"
 	"sort"
 	"strings"
 	"text/template"
@@ -63,22 +64,40 @@ type serviceSource struct {
 	nodeInformer                   coreinformers.NodeInformer
 	serviceTypeFilter              map[string]struct{}
 	labelSelector                  labels.Selector
+	nodeAddressPreference          v1.NodeAddressType
+	nodePortLabelSelector          labels.Selector
+	preferLBTarget                 string
 }
 
 // NewServiceSource creates a new serviceSource with the given config.
-func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, namespace, annotationFilter string, fqdnTemplate string, combineFqdnAnnotation bool, compatibility string, publishInternal bool, publishHostIP bool, alwaysPublishNotReadyAddresses bool, serviceTypeFilter []string, ignoreHostnameAnnotation bool, labelSelector labels.Selector) (Source, error) {
+func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, namespace, annotationFilter string, fqdnTemplate string, combineFqdnAnnotation bool, compatibility string, publishInternal bool, publishHostIP bool, alwaysPublishNotReadyAddresses bool, serviceTypeFilter []string, ignoreHostnameAnnotation bool, labelSelector labels.Selector, nodeAddressPreference v1.NodeAddressType, nodePortLabelSelector labels.Selector, preferLBTarget string) (Source, error) {
+	return newServiceSource(ctx, kubeClient, nil, namespace, annotationFilter, fqdnTemplate, combineFqdnAnnotation, compatibility, publishInternal, publishHostIP, alwaysPublishNotReadyAddresses, serviceTypeFilter, ignoreHostnameAnnotation, labelSelector, nodeAddressPreference, nodePortLabelSelector, preferLBTarget)
+}
+
+// newServiceSource creates a new serviceSource which reads the nodes from the given informer. Without
+// an informer, one is started if the nodes can be needed at all, see serviceSourceNeedsNodes.
+func newServiceSource(ctx context.Context, kubeClient kubernetes.Interface, nodeInformer coreinformers.NodeInformer, namespace, annotationFilter string, fqdnTemplate string, combineFqdnAnnotation bool, compatibility string, publishInternal bool, publishHostIP bool, alwaysPublishNotReadyAddresses bool, serviceTypeFilter []string, ignoreHostnameAnnotation bool, labelSelector labels.Selector, nodeAddressPreference v1.NodeAddressType, nodePortLabelSelector labels.Selector, preferLBTarget string) (Source, error) {
 	tmpl, err := parseTemplate(fqdnTemplate)
 	if err != nil {
 		return nil, err
 	}
 
-	// Use shared informers to listen for add/update/delete of services/pods/nodes in the specified namespace.
+	if nodeInformer == nil && serviceSourceNeedsNodes(serviceTypeFilter, compatibility) {
+		nodeInformer, err = newNodeInformer(ctx, kubeClient)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// Use shared informers to listen for add/update/delete of services/endpoints/pods in the specified namespace.
+	// Services have their own factory so that only the ones matching the label selector are listed
+	// from the API server, while endpoints and pods are unaffected by it.
 	// Set resync period to 0, to prevent processing when nothing has changed
+	serviceInformerFactory := newLabelFilteredInformerFactory(kubeClient, namespace, labelSelector)
+	serviceInformer := serviceInformerFactory.Core().V1().Services()
 	informerFactory := kubeinformers.NewSharedInformerFactoryWithOptions(kubeClient, 0, kubeinformers.WithNamespace(namespace))
-	serviceInformer := informerFactory.Core().V1().Services()
 	endpointsInformer := informerFactory.Core().V1().Endpoints()
 	podInformer := informerFactory.Core().V1().Pods()
-	nodeInformer := informerFactory.Core().V1().Nodes()
 
 	// Add default resource event handlers to properly initialize informer.
 	serviceInformer.Informer().AddEventHandler(
@@ -99,16 +118,14 @@ func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, name
 			},
 		},
 	)
-	nodeInformer.Informer().AddEventHandler(
-		cache.ResourceEventHandlerFuncs{
-			AddFunc: func(obj interface{}) {
-			},
-		},
-	)
 
+	serviceInformerFactory.Start(ctx.Done())
 	informerFactory.Start(ctx.Done())
 
 	// wait for the local cache to be populated.
+	if err := waitForCacheSync(context.Background(), serviceInformerFactory); err != nil {
+		return nil, err
+	}
 	if err := waitForCacheSync(context.Background(), informerFactory); err != nil {
 		return nil, err
 	}
@@ -120,6 +137,10 @@ func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, name
 		serviceTypes[serviceType] = struct{}{}
 	}
 
+	if nodePortLabelSelector == nil {
+		nodePortLabelSelector = labels.Everything()
+	}
+
 	return &serviceSource{
 		client:                         kubeClient,
 		namespace:                      namespace,
@@ -137,9 +158,49 @@ func NewServiceSource(ctx context.Context, kubeClient kubernetes.Interface, name
 		nodeInformer:                   nodeInformer,
 		serviceTypeFilter:              serviceTypes,
 		labelSelector:                  labelSelector,
+		nodeAddressPreference:          nodeAddressPreference,
+		nodePortLabelSelector:          nodePortLabelSelector,
+		preferLBTarget:                 preferLBTarget,
 	}, nil
 }
 
+// serviceSourceNeedsNodes reports whether a service source may publish node addresses: for NodePort
+// services, for headless services, which are of type ClusterIP, and with the kops-dns-controller
+// compatibility. Nodes aren't namespaced, so only then does the source need to watch them cluster-wide.
+func serviceSourceNeedsNodes(serviceTypeFilter []string, compatibility string) bool {
+	if len(serviceTypeFilter) == 0 || compatibility == "kops-dns-controller" {
+		return true
+	}
+	for _, serviceType := range serviceTypeFilter {
+		if serviceType == string(v1.ServiceTypeNodePort) || serviceType == string(v1.ServiceTypeClusterIP) {
+			return true
+		}
+	}
+	return false
+}
+
+// newNodeInformer starts an informer for the nodes of the cluster and waits for its cache to be populated.
+func newNodeInformer(ctx context.Context, kubeClient kubernetes.Interface) (coreinformers.NodeInformer, error) {
+	informerFactory := kubeinformers.NewSharedInformerFactory(kubeClient, 0)
+	nodeInformer := informerFactory.Core().V1().Nodes()
+
+	// Add default resource event handlers to properly initialize informer.
+	nodeInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {
+			},
+		},
+	)
+
+	informerFactory.Start(ctx.Done())
+
+	// wait for the local cache to be populated.
+	if err := waitForCacheSync(context.Background(), informerFactory); err != nil {
+		return nil, err
+	}
+	return nodeInformer, nil
+}
+
 // Endpoints returns endpoint objects for each service that should be processed.
 func (sc *serviceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
 	services, err := sc.serviceInformer.Lister().Services(sc.namespace).List(sc.labelSelector)
@@ -167,6 +228,11 @@ func (sc *serviceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 			continue
 		}
 
+		if isExcluded(svc.Annotations) {
+			log.Debugf("Skipping service %s/%s because it is excluded from DNS management", svc.Namespace, svc.Name)
+			continue
+		}
+
 		svcEndpoints := sc.endpoints(svc)
 
 		// process legacy annotations if no endpoints were returned and compatibility mode is enabled.
@@ -185,7 +251,7 @@ func (sc *serviceSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, e
 			}
 
 			if sc.combineFQDNAnnotation {
-				svcEndpoints = append(svcEndpoints, sEndpoints...)
+				svcEndpoints = mergeEndpoints(svcEndpoints, sEndpoints)
 			} else {
 				svcEndpoints = sEndpoints
 			}
@@ -443,9 +509,9 @@ func (sc *serviceSource) setResourceLabel(service *v1.Service, endpoints []*endp
 
 func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, providerSpecific endpoint.ProviderSpecific, setIdentifier string, useClusterIP bool) []*endpoint.Endpoint {
 	hostname = strings.TrimSuffix(hostname, ".")
-	ttl, err := getTTLFromAnnotations(svc.Annotations)
+	ttl, err := getTTLForHostnameFromAnnotations(svc.Annotations, hostname)
 	if err != nil {
-		log.Warn(err)
+		log.Warnf("Ignoring the TTL annotation of service %s/%s for %s: %v", svc.Namespace, svc.Name, hostname, err)
 	}
 
 	epA := &endpoint.Endpoint{
@@ -456,6 +522,14 @@ func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, pro
 		DNSName:    hostname,
 	}
 
+	epAAAA := &endpoint.Endpoint{
+		RecordTTL:  ttl,
+		RecordType: endpoint.RecordTypeAAAA,
+		Labels:     endpoint.NewLabels(),
+		Targets:    make(endpoint.Targets, 0, defaultTargetsCapacity),
+		DNSName:    hostname,
+	}
+
 	epCNAME := &endpoint.Endpoint{
 		RecordTTL:  ttl,
 		RecordType: endpoint.RecordTypeCNAME,
@@ -472,7 +546,7 @@ func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, pro
 		if useClusterIP {
 			targets = append(targets, extractServiceIps(svc)...)
 		} else {
-			targets = append(targets, extractLoadBalancerTargets(svc)...)
+			targets = append(targets, extractLoadBalancerTargets(svc, sc.preferLBTarget)...)
 		}
 	case v1.ServiceTypeClusterIP:
 		if sc.publishInternal {
@@ -494,10 +568,12 @@ func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, pro
 	}
 
 	for _, t := range targets {
-		if suitableType(t) == endpoint.RecordTypeA {
+		switch suitableType(t) {
+		case endpoint.RecordTypeA:
 			epA.Targets = append(epA.Targets, t)
-		}
-		if suitableType(t) == endpoint.RecordTypeCNAME {
+		case endpoint.RecordTypeAAAA:
+			epAAAA.Targets = append(epAAAA.Targets, canonicalIPv6(t))
+		case endpoint.RecordTypeCNAME:
 			epCNAME.Targets = append(epCNAME.Targets, t)
 		}
 	}
@@ -505,6 +581,9 @@ func (sc *serviceSource) generateEndpoints(svc *v1.Service, hostname string, pro
 	if len(epA.Targets) > 0 {
 		endpoints = append(endpoints, epA)
 	}
+	if len(epAAAA.Targets) > 0 {
+		endpoints = append(endpoints, epAAAA)
+	}
 	if len(epCNAME.Targets) > 0 {
 		endpoints = append(endpoints, epCNAME)
 	}
@@ -520,28 +599,27 @@ func extractServiceIps(svc *v1.Service) endpoint.Targets {
 		log.Debugf("Unable to associate %s headless service with a Cluster IP", svc.Name)
 		return endpoint.Targets{}
 	}
+	// dual-stack services have a cluster IP of each family
+	if len(svc.Spec.ClusterIPs) > 0 {
+		return append(endpoint.Targets{}, svc.Spec.ClusterIPs...)
+	}
 	return endpoint.Targets{svc.Spec.ClusterIP}
 }
 
 func extractServiceExternalName(svc *v1.Service) endpoint.Targets {
+	externalName := strings.TrimSuffix(svc.Spec.ExternalName, ".")
+	if net.ParseIP(externalName) == nil && (strings.HasPrefix(externalName, "*") || !isValidHostname(externalName)) {
+		log.Warnf("Ignoring the external name %q of service %s/%s, it is neither an IP address nor a valid hostname", svc.Spec.ExternalName, svc.Namespace, svc.Name)
+		return endpoint.Targets{}
+	}
 	return endpoint.Targets{svc.Spec.ExternalName}
 }
 
-func extractLoadBalancerTargets(svc *v1.Service) endpoint.Targets {
-	var (
-		targets     endpoint.Targets
-		externalIPs endpoint.Targets
-	)
+func extractLoadBalancerTargets(svc *v1.Service, preferLBTarget string) endpoint.Targets {
+	var externalIPs endpoint.Targets
 
 	// Create a corresponding endpoint for each configured external entrypoint.
-	for _, lb := range svc.Status.LoadBalancer.Ingress {
-		if lb.IP != "" {
-			targets = append(targets, lb.IP)
-		}
-		if lb.Hostname != "" {
-			targets = append(targets, lb.Hostname)
-		}
-	}
+	targets := targetsFromLoadBalancerStatus(svc.Status.LoadBalancer, preferLBTarget, fmt.Sprintf("service %s/%s", svc.Namespace, svc.Name))
 
 	if svc.Spec.ExternalIPs != nil {
 		for _, ext := range svc.Spec.ExternalIPs {
@@ -587,6 +665,9 @@ func (sc *serviceSource) extractNodePortTargets(svc *v1.Service) (endpoint.Targe
 					log.Debugf("Unable to find node where Pod %s is running", v.Spec.Hostname)
 					continue
 				}
+				if !sc.nodePortLabelSelector.Matches(labels.Set(node.Labels)) {
+					continue
+				}
 				if _, ok := nodesMap[node]; !ok {
 					nodesMap[node] = *new(struct{})
 					nodes = append(nodes, node)
@@ -594,13 +675,18 @@ func (sc *serviceSource) extractNodePortTargets(svc *v1.Service) (endpoint.Targe
 			}
 		}
 	default:
-		nodes, err = sc.nodeInformer.Lister().List(labels.Everything())
+		nodes, err = sc.nodeInformer.Lister().List(sc.nodePortLabelSelector)
 		if err != nil {
 			return nil, err
 		}
 	}
 
 	for _, node := range nodes {
+		// Cordoned nodes are about to be drained, don't publish them.
+		if node.Spec.Unschedulable {
+			log.Debugf("Skipping unschedulable node %s", node.Name)
+			continue
+		}
 		for _, address := range node.Status.Addresses {
 			switch address.Type {
 			case v1.NodeExternalIP:
@@ -618,6 +704,9 @@ func (sc *serviceSource) extractNodePortTargets(svc *v1.Service) (endpoint.Targe
 	if access == "private" {
 		return internalIPs, nil
 	}
+	if sc.nodeAddressPreference == v1.NodeInternalIP && len(internalIPs) > 0 {
+		return internalIPs, nil
+	}
 	if len(externalIPs) > 0 {
 		return externalIPs, nil
 	}
@@ -668,5 +757,5 @@ func (sc *serviceSource) AddEventHandler(ctx context.Context, handler func()) {
 
 	// Right now there is no way to remove event handler from informer, see:
 	// https://github.com/kubernetes/kubernetes/issues/79610
-	sc.serviceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	sc.serviceInformer.Informer().AddEventHandler(unlessExcludedEventHandlerFunc(handler))
 }
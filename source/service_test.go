@@ -78,6 +78,9 @@ func (suite *ServiceSuite) SetupTest() {
 		[]string{},
 		false,
 		labels.Everything(),
+		v1.NodeExternalIP,
+		labels.Everything(),
+		LoadBalancerTargetBoth,
 	)
 	suite.NoError(err, "should initialize service source")
 }
@@ -158,6 +161,9 @@ func testServiceSourceNewServiceSource(t *testing.T) {
 				ti.serviceTypesFilter,
 				false,
 				labels.Everything(),
+				v1.NodeExternalIP,
+				labels.Everything(),
+				LoadBalancerTargetBoth,
 			)
 
 			if ti.expectError {
@@ -235,6 +241,23 @@ func testServiceSourceEndpoints(t *testing.T) {
 				{DNSName: "foo.example.org", Targets: endpoint.Targets{"1.2.3.4"}},
 			},
 		},
+		{
+			title:        "annotated dual-stack services return an A and an AAAA endpoint",
+			svcNamespace: "testing",
+			svcName:      "foo",
+			svcType:      v1.ServiceTypeLoadBalancer,
+			labels:       map[string]string{},
+			annotations: map[string]string{
+				hostnameAnnotationKey: "foo.example.org.",
+			},
+			externalIPs:        []string{},
+			lbs:                []string{"1.2.3.4", "2001:0db8:0:0::1"},
+			serviceTypesFilter: []string{},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.2.3.4"}},
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeAAAA, Targets: endpoint.Targets{"2001:db8::1"}},
+			},
+		},
 		{
 			title:                    "hostname annotation on services is ignored",
 			svcNamespace:             "testing",
@@ -319,6 +342,43 @@ func testServiceSourceEndpoints(t *testing.T) {
 				{DNSName: "foo.fqdn.com", Targets: endpoint.Targets{"1.2.3.4"}},
 			},
 		},
+		{
+			title:                    "FQDN template using labels combined with annotation returns each hostname once",
+			svcNamespace:             "testing",
+			svcName:                  "foo",
+			svcType:                  v1.ServiceTypeLoadBalancer,
+			fqdnTemplate:             "{{.Name}}.{{.Labels.team}}.example.org,{{.Name}}.example.org",
+			combineFQDNAndAnnotation: true,
+			labels:                   map[string]string{"team": "payments"},
+			annotations: map[string]string{
+				hostnameAnnotationKey: "foo.example.org.",
+			},
+			externalIPs:        []string{},
+			lbs:                []string{"1.2.3.4"},
+			serviceTypesFilter: []string{},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "foo.example.org", Targets: endpoint.Targets{"1.2.3.4"}},
+				{DNSName: "foo.payments.example.org", Targets: endpoint.Targets{"1.2.3.4"}},
+			},
+		},
+		{
+			title:                    "FQDN template producing an invalid hostname only skips that hostname",
+			svcNamespace:             "testing",
+			svcName:                  "foo",
+			svcType:                  v1.ServiceTypeLoadBalancer,
+			fqdnTemplate:             "{{.Name}}.{{.Labels.team}}.example.org",
+			combineFQDNAndAnnotation: true,
+			labels:                   map[string]string{"team": "not a label!"},
+			annotations: map[string]string{
+				hostnameAnnotationKey: "foo.example.org.",
+			},
+			externalIPs:        []string{},
+			lbs:                []string{"1.2.3.4"},
+			serviceTypesFilter: []string{},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "foo.example.org", Targets: endpoint.Targets{"1.2.3.4"}},
+			},
+		},
 		{
 			title:                    "FQDN template and annotation both with multiple hostnames while ignoring annotations will only return FQDN endpoints",
 			svcNamespace:             "testing",
@@ -439,6 +499,21 @@ func testServiceSourceEndpoints(t *testing.T) {
 			serviceTypesFilter: []string{},
 			expected:           []*endpoint.Endpoint{},
 		},
+		{
+			title:        "excluded services are ignored",
+			svcNamespace: "testing",
+			svcName:      "foo",
+			svcType:      v1.ServiceTypeLoadBalancer,
+			labels:       map[string]string{},
+			annotations: map[string]string{
+				excludeAnnotationKey:  "true",
+				hostnameAnnotationKey: "foo.example.org.",
+			},
+			externalIPs:        []string{},
+			lbs:                []string{"1.2.3.4"},
+			serviceTypesFilter: []string{},
+			expected:           []*endpoint.Endpoint{},
+		},
 		{
 			title:           "services are found in target namespace",
 			targetNamespace: "testing",
@@ -858,6 +933,60 @@ func testServiceSourceEndpoints(t *testing.T) {
 				{DNSName: "foo.example.org", Targets: endpoint.Targets{"1.2.3.4"}, RecordTTL: endpoint.TTL(0)},
 			},
 		},
+		{
+			title:        "ttl annotated with one value per hostname should set Record.TTL of each hostname",
+			svcNamespace: "testing",
+			svcName:      "foo",
+			svcType:      v1.ServiceTypeLoadBalancer,
+			labels:       map[string]string{},
+			annotations: map[string]string{
+				hostnameAnnotationKey: "cdn.example.org., failover.example.org.",
+				ttlAnnotationKey:      "86400, 1m",
+			},
+			externalIPs:        []string{},
+			lbs:                []string{"1.2.3.4"},
+			serviceTypesFilter: []string{},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "cdn.example.org", Targets: endpoint.Targets{"1.2.3.4"}, RecordTTL: endpoint.TTL(86400)},
+				{DNSName: "failover.example.org", Targets: endpoint.Targets{"1.2.3.4"}, RecordTTL: endpoint.TTL(60)},
+			},
+		},
+		{
+			title:        "ttl annotated with a single value should set Record.TTL of all hostnames",
+			svcNamespace: "testing",
+			svcName:      "foo",
+			svcType:      v1.ServiceTypeLoadBalancer,
+			labels:       map[string]string{},
+			annotations: map[string]string{
+				hostnameAnnotationKey: "cdn.example.org., failover.example.org.",
+				ttlAnnotationKey:      "10",
+			},
+			externalIPs:        []string{},
+			lbs:                []string{"1.2.3.4"},
+			serviceTypesFilter: []string{},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "cdn.example.org", Targets: endpoint.Targets{"1.2.3.4"}, RecordTTL: endpoint.TTL(10)},
+				{DNSName: "failover.example.org", Targets: endpoint.Targets{"1.2.3.4"}, RecordTTL: endpoint.TTL(10)},
+			},
+		},
+		{
+			title:        "ttl annotated with more values than hostnames is not valid",
+			svcNamespace: "testing",
+			svcName:      "foo",
+			svcType:      v1.ServiceTypeLoadBalancer,
+			labels:       map[string]string{},
+			annotations: map[string]string{
+				hostnameAnnotationKey: "cdn.example.org., failover.example.org.",
+				ttlAnnotationKey:      "86400,60,10",
+			},
+			externalIPs:        []string{},
+			lbs:                []string{"1.2.3.4"},
+			serviceTypesFilter: []string{},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "cdn.example.org", Targets: endpoint.Targets{"1.2.3.4"}, RecordTTL: endpoint.TTL(0)},
+				{DNSName: "failover.example.org", Targets: endpoint.Targets{"1.2.3.4"}, RecordTTL: endpoint.TTL(0)},
+			},
+		},
 		{
 			title:        "filter on service types should include matching services",
 			svcNamespace: "testing",
@@ -1055,6 +1184,9 @@ func testServiceSourceEndpoints(t *testing.T) {
 				tc.serviceTypesFilter,
 				tc.ignoreHostnameAnnotation,
 				sourceLabel,
+				v1.NodeExternalIP,
+				labels.Everything(),
+				LoadBalancerTargetBoth,
 			)
 
 			require.NoError(t, err)
@@ -1244,6 +1376,9 @@ func testMultipleServicesEndpoints(t *testing.T) {
 				tc.serviceTypesFilter,
 				tc.ignoreHostnameAnnotation,
 				labels.Everything(),
+				v1.NodeExternalIP,
+				labels.Everything(),
+				LoadBalancerTargetBoth,
 			)
 			require.NoError(t, err)
 
@@ -1290,6 +1425,7 @@ func TestClusterIpServices(t *testing.T) {
 		labels                   map[string]string
 		annotations              map[string]string
 		clusterIP                string
+		clusterIPs               []string
 		expected                 []*endpoint.Endpoint
 		expectError              bool
 		labelSelector            string
@@ -1307,6 +1443,21 @@ func TestClusterIpServices(t *testing.T) {
 				{DNSName: "foo.example.org", Targets: endpoint.Targets{"1.2.3.4"}},
 			},
 		},
+		{
+			title:        "annotated dual-stack ClusterIp services return an A and an AAAA endpoint",
+			svcNamespace: "testing",
+			svcName:      "foo",
+			svcType:      v1.ServiceTypeClusterIP,
+			annotations: map[string]string{
+				hostnameAnnotationKey: "foo.example.org.",
+			},
+			clusterIP:  "1.2.3.4",
+			clusterIPs: []string{"1.2.3.4", "fd00::1"},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeA, Targets: endpoint.Targets{"1.2.3.4"}},
+				{DNSName: "foo.example.org", RecordType: endpoint.RecordTypeAAAA, Targets: endpoint.Targets{"fd00::1"}},
+			},
+		},
 		{
 			title:                    "hostname annotated ClusterIp services are ignored",
 			svcNamespace:             "testing",
@@ -1373,8 +1524,9 @@ func TestClusterIpServices(t *testing.T) {
 			// Create a service to test against
 			service := &v1.Service{
 				Spec: v1.ServiceSpec{
-					Type:      tc.svcType,
-					ClusterIP: tc.clusterIP,
+					Type:       tc.svcType,
+					ClusterIP:  tc.clusterIP,
+					ClusterIPs: tc.clusterIPs,
 				},
 				ObjectMeta: metav1.ObjectMeta{
 					Namespace:   tc.svcNamespace,
@@ -1409,6 +1561,9 @@ func TestClusterIpServices(t *testing.T) {
 				[]string{},
 				tc.ignoreHostnameAnnotation,
 				labelSelector,
+				v1.NodeExternalIP,
+				labels.Everything(),
+				LoadBalancerTargetBoth,
 			)
 			require.NoError(t, err)
 
@@ -1450,6 +1605,8 @@ func TestServiceSourceNodePortServices(t *testing.T) {
 		nodeIndex                []int
 		phases                   []v1.PodPhase
 		labelSelector            labels.Selector
+		nodeAddressPreference    v1.NodeAddressType
+		nodePortLabelSelector    labels.Selector
 	}{
 		{
 			title:            "annotated NodePort services return an endpoint with IP addresses of the cluster's nodes",
@@ -1486,6 +1643,90 @@ func TestServiceSourceNodePortServices(t *testing.T) {
 				},
 			}},
 		},
+		{
+			title:                 "annotated NodePort services return the internal IP addresses of the nodes if preferred",
+			svcNamespace:          "testing",
+			svcName:               "foo",
+			svcType:               v1.ServiceTypeNodePort,
+			svcTrafficPolicy:      v1.ServiceExternalTrafficPolicyTypeCluster,
+			nodeAddressPreference: v1.NodeInternalIP,
+			annotations: map[string]string{
+				hostnameAnnotationKey: "foo.example.org.",
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "_foo._tcp.foo.example.org", Targets: endpoint.Targets{"0 50 30192 foo.example.org"}, RecordType: endpoint.RecordTypeSRV},
+				{DNSName: "foo.example.org", Targets: endpoint.Targets{"10.0.1.1", "10.0.1.2"}, RecordType: endpoint.RecordTypeA},
+			},
+			nodes: []*v1.Node{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node1",
+				},
+				Status: v1.NodeStatus{
+					Addresses: []v1.NodeAddress{
+						{Type: v1.NodeExternalIP, Address: "54.10.11.1"},
+						{Type: v1.NodeInternalIP, Address: "10.0.1.1"},
+					},
+				},
+			}, {
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node2",
+				},
+				Status: v1.NodeStatus{
+					Addresses: []v1.NodeAddress{
+						{Type: v1.NodeExternalIP, Address: "54.10.11.2"},
+						{Type: v1.NodeInternalIP, Address: "10.0.1.2"},
+					},
+				},
+			}},
+		},
+		{
+			title:                 "annotated NodePort services skip unschedulable nodes and nodes not matching the node label selector",
+			svcNamespace:          "testing",
+			svcName:               "foo",
+			svcType:               v1.ServiceTypeNodePort,
+			svcTrafficPolicy:      v1.ServiceExternalTrafficPolicyTypeCluster,
+			nodePortLabelSelector: labels.SelectorFromSet(labels.Set{"ingress": "true"}),
+			annotations: map[string]string{
+				hostnameAnnotationKey: "foo.example.org.",
+			},
+			expected: []*endpoint.Endpoint{
+				{DNSName: "_foo._tcp.foo.example.org", Targets: endpoint.Targets{"0 50 30192 foo.example.org"}, RecordType: endpoint.RecordTypeSRV},
+				{DNSName: "foo.example.org", Targets: endpoint.Targets{"54.10.11.1"}, RecordType: endpoint.RecordTypeA},
+			},
+			nodes: []*v1.Node{{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node1",
+					Labels: map[string]string{"ingress": "true"},
+				},
+				Status: v1.NodeStatus{
+					Addresses: []v1.NodeAddress{
+						{Type: v1.NodeExternalIP, Address: "54.10.11.1"},
+					},
+				},
+			}, {
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   "node2",
+					Labels: map[string]string{"ingress": "true"},
+				},
+				Spec: v1.NodeSpec{
+					Unschedulable: true,
+				},
+				Status: v1.NodeStatus{
+					Addresses: []v1.NodeAddress{
+						{Type: v1.NodeExternalIP, Address: "54.10.11.2"},
+					},
+				},
+			}, {
+				ObjectMeta: metav1.ObjectMeta{
+					Name: "node3",
+				},
+				Status: v1.NodeStatus{
+					Addresses: []v1.NodeAddress{
+						{Type: v1.NodeExternalIP, Address: "54.10.11.3"},
+					},
+				},
+			}},
+		},
 		{
 			title:                    "hostname annotated NodePort services are ignored",
 			svcNamespace:             "testing",
@@ -1979,6 +2220,9 @@ func TestServiceSourceNodePortServices(t *testing.T) {
 				[]string{},
 				tc.ignoreHostnameAnnotation,
 				labels.Everything(),
+				tc.nodeAddressPreference,
+				tc.nodePortLabelSelector,
+				LoadBalancerTargetBoth,
 			)
 			require.NoError(t, err)
 
@@ -2452,6 +2696,9 @@ func TestHeadlessServices(t *testing.T) {
 				[]string{},
 				tc.ignoreHostnameAnnotation,
 				labels.Everything(),
+				v1.NodeExternalIP,
+				labels.Everything(),
+				LoadBalancerTargetBoth,
 			)
 			require.NoError(t, err)
 
@@ -2809,6 +3056,9 @@ func TestHeadlessServicesHostIP(t *testing.T) {
 				[]string{},
 				tc.ignoreHostnameAnnotation,
 				labels.Everything(),
+				v1.NodeExternalIP,
+				labels.Everything(),
+				LoadBalancerTargetBoth,
 			)
 			require.NoError(t, err)
 
@@ -2882,6 +3132,23 @@ func TestExternalServices(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"external services return no endpoint for an external name that is no valid hostname",
+			"",
+			"testing",
+			"foo",
+			v1.ServiceTypeExternalName,
+			"",
+			"",
+			false,
+			map[string]string{"component": "foo"},
+			map[string]string{
+				hostnameAnnotationKey: "service.example.org",
+			},
+			"remote example.com",
+			[]*endpoint.Endpoint{},
+			false,
+		},
 	} {
 		tc := tc
 		t.Run(tc.title, func(t *testing.T) {
@@ -2921,6 +3188,9 @@ func TestExternalServices(t *testing.T) {
 				[]string{},
 				tc.ignoreHostnameAnnotation,
 				labels.Everything(),
+				v1.NodeExternalIP,
+				labels.Everything(),
+				LoadBalancerTargetBoth,
 			)
 			require.NoError(t, err)
 
@@ -2975,6 +3245,9 @@ func BenchmarkServiceEndpoints(b *testing.B) {
 		[]string{},
 		false,
 		labels.Everything(),
+		v1.NodeExternalIP,
+		labels.Everything(),
+		LoadBalancerTargetBoth,
 	)
 	require.NoError(b, err)
 
@@ -0,0 +1,283 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"text/template"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	discoveryinformers "k8s.io/client-go/informers/discovery/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+var serviceImportGVR = schema.GroupVersionResource{
+	Group:    "multicluster.x-k8s.io",
+	Version:  "v1alpha1",
+	Resource: "serviceimports",
+}
+
+const (
+	// mcsServiceNameLabel is the label of the EndpointSlices of a ServiceImport naming it
+	mcsServiceNameLabel = "multicluster.kubernetes.io/service-name"
+	// mcsSourceClusterLabel is the label of the EndpointSlices of a ServiceImport naming the exporting cluster
+	mcsSourceClusterLabel = "multicluster.kubernetes.io/source-cluster"
+)
+
+// serviceImportSource is an implementation of Source for ServiceImport objects of the Multi-Cluster
+// Services API. The hostnames are taken from the hostname annotation and the FQDN template, the
+// targets are the ready addresses of the EndpointSlices imported from all exporting clusters.
+type serviceImportSource struct {
+	namespace             string
+	annotationFilter      string
+	fqdnTemplate          *template.Template
+	serviceImportInformer informers.GenericInformer
+	endpointSliceInformer discoveryinformers.EndpointSliceInformer
+}
+
+// NewServiceImportSource creates a new serviceImportSource with the given config.
+func NewServiceImportSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string, fqdnTemplate string) (Source, error) {
+	tmpl, err := parseTemplate(fqdnTemplate)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use shared informers to listen for add/update/delete of ServiceImports and EndpointSlices in the specified namespace.
+	// Set resync period to 0, to prevent processing when nothing has changed.
+	dynamicInformerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicKubeClient, 0, namespace, nil)
+	serviceImportInformer := dynamicInformerFactory.ForResource(serviceImportGVR)
+	// Only the EndpointSlices imported for a ServiceImport are listed and cached.
+	informerFactory := informers.NewSharedInformerFactoryWithOptions(kubeClient, 0, informers.WithNamespace(namespace), informers.WithTweakListOptions(func(o *metav1.ListOptions) {
+		o.LabelSelector = mcsServiceNameLabel
+	}))
+	endpointSliceInformer := informerFactory.Discovery().V1().EndpointSlices()
+
+	// Add default resource event handlers to properly initialize informers.
+	serviceImportInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {},
+		},
+	)
+	endpointSliceInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {},
+		},
+	)
+
+	dynamicInformerFactory.Start(ctx.Done())
+	informerFactory.Start(ctx.Done())
+
+	// wait for the local caches to be populated.
+	if err := waitForDynamicCacheSync(context.Background(), dynamicInformerFactory); err != nil {
+		return nil, err
+	}
+	if err := waitForCacheSync(context.Background(), informerFactory); err != nil {
+		return nil, err
+	}
+
+	return &serviceImportSource{
+		namespace:             namespace,
+		annotationFilter:      annotationFilter,
+		fqdnTemplate:          tmpl,
+		serviceImportInformer: serviceImportInformer,
+		endpointSliceInformer: endpointSliceInformer,
+	}, nil
+}
+
+// Endpoints returns endpoint objects for each host-target combination that should be processed.
+// Retrieves all ServiceImports in the source's namespace(s).
+func (sc *serviceImportSource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	objs, err := sc.serviceImportInformer.Lister().ByNamespace(sc.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := metav1.ParseToLabelSelector(sc.annotationFilter)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var endpoints []*endpoint.Endpoint
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, errors.New("could not convert")
+		}
+		serviceImport := &ServiceImport{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, serviceImport); err != nil {
+			return nil, err
+		}
+
+		// include the ServiceImport if its annotations match the selector
+		if !selector.Empty() && !selector.Matches(labels.Set(serviceImport.Annotations)) {
+			continue
+		}
+
+		// Check controller annotation to see if we are responsible.
+		controller, ok := serviceImport.Annotations[controllerAnnotationKey]
+		if ok && controller != controllerAnnotationValue {
+			log.Debugf("Skipping ServiceImport %s/%s because controller value does not match, found: %s, required: %s",
+				serviceImport.Namespace, serviceImport.Name, controller, controllerAnnotationValue)
+			continue
+		}
+
+		siEndpoints, err := sc.endpointsFromServiceImport(serviceImport)
+		if err != nil {
+			return nil, err
+		}
+		if len(siEndpoints) == 0 {
+			log.Debugf("No endpoints could be generated from ServiceImport %s/%s", serviceImport.Namespace, serviceImport.Name)
+			continue
+		}
+
+		log.Debugf("Endpoints generated from ServiceImport: %s/%s: %v", serviceImport.Namespace, serviceImport.Name, siEndpoints)
+		for _, ep := range siEndpoints {
+			ep.Labels[endpoint.ResourceLabelKey] = fmt.Sprintf("serviceimport/%s/%s", serviceImport.Namespace, serviceImport.Name)
+		}
+		endpoints = append(endpoints, siEndpoints...)
+	}
+
+	for _, ep := range endpoints {
+		sort.Sort(ep.Targets)
+	}
+
+	return endpoints, nil
+}
+
+// endpointsFromServiceImport returns the endpoints of a ServiceImport.
+func (sc *serviceImportSource) endpointsFromServiceImport(serviceImport *ServiceImport) ([]*endpoint.Endpoint, error) {
+	hostnames := getHostnamesFromAnnotations(serviceImport.Annotations)
+	if sc.fqdnTemplate != nil {
+		templateHostnames, err := execTemplate(sc.fqdnTemplate, serviceImport)
+		if err != nil {
+			return nil, err
+		}
+		hostnames = append(hostnames, templateHostnames...)
+	}
+	if len(hostnames) == 0 {
+		return nil, nil
+	}
+
+	targets := getTargetsFromTargetAnnotation(serviceImport.Annotations)
+	if len(targets) == 0 {
+		var err error
+		targets, err = sc.targetsFromEndpointSlices(serviceImport)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ttl, err := getTTLFromAnnotations(serviceImport.Annotations)
+	if err != nil {
+		log.Warnf("Ignoring the TTL annotation of ServiceImport %s/%s: %v", serviceImport.Namespace, serviceImport.Name, err)
+	}
+	providerSpecific, setIdentifier := getProviderSpecificAnnotations(serviceImport.Annotations)
+
+	var endpoints []*endpoint.Endpoint
+	for _, hostname := range hostnames {
+		endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier)...)
+	}
+	return endpoints, nil
+}
+
+// targetsFromEndpointSlices returns the union of the ready addresses of all clusters exporting the
+// ServiceImport. A cluster without ready endpoints contributes no targets.
+func (sc *serviceImportSource) targetsFromEndpointSlices(serviceImport *ServiceImport) (endpoint.Targets, error) {
+	slices, err := sc.endpointSliceInformer.Lister().EndpointSlices(serviceImport.Namespace).List(labels.SelectorFromSet(labels.Set{
+		mcsServiceNameLabel: serviceImport.Name,
+	}))
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]struct{}{}
+	var targets endpoint.Targets
+	for _, slice := range slices {
+		if slice.AddressType == discoveryv1.AddressTypeFQDN {
+			continue
+		}
+		var ready int
+		for _, ep := range slice.Endpoints {
+			// An unknown readiness counts as ready.
+			if ep.Conditions.Ready != nil && !*ep.Conditions.Ready {
+				continue
+			}
+			ready++
+			for _, address := range ep.Addresses {
+				if _, ok := seen[address]; !ok {
+					seen[address] = struct{}{}
+					targets = append(targets, address)
+				}
+			}
+		}
+		if ready == 0 {
+			log.Debugf("Skipping cluster %q of ServiceImport %s/%s without ready endpoints in EndpointSlice %s",
+				slice.Labels[mcsSourceClusterLabel], serviceImport.Namespace, serviceImport.Name, slice.Name)
+		}
+	}
+	return targets, nil
+}
+
+func (sc *serviceImportSource) AddEventHandler(ctx context.Context, handler func()) {
+	log.Debug("Adding event handler for ServiceImport")
+
+	sc.serviceImportInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	sc.endpointSliceInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+}
+
+// ServiceImport holds the fields of a ServiceImport used by the source, see
+// https://github.com/kubernetes-sigs/mcs-api/blob/master/pkg/apis/v1alpha1/serviceimport.go
+type ServiceImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec serviceImportSpec `json:"spec,omitempty"`
+}
+
+type serviceImportSpec struct {
+	Type string   `json:"type,omitempty"`
+	IPs  []string `json:"ips,omitempty"`
+}
+
+func (in *ServiceImport) DeepCopyObject() runtime.Object {
+	out := *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	if in.Spec.IPs != nil {
+		out.Spec.IPs = make([]string, len(in.Spec.IPs))
+		copy(out.Spec.IPs, in.Spec.IPs)
+	}
+	return &out
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// This is a compile-time validation that serviceImportSource is a Source.
+var _ Source = &serviceImportSource{}
+
+func newTestServiceImport(name string, annotations map[string]string) *unstructured.Unstructured {
+	serviceImport := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": serviceImportGVR.GroupVersion().String(),
+			"kind":       "ServiceImport",
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": "default",
+			},
+			"spec": map[string]interface{}{
+				"type": "ClusterSetIP",
+				"ips":  []interface{}{"10.0.0.1"},
+			},
+		},
+	}
+	serviceImport.SetAnnotations(annotations)
+	return serviceImport
+}
+
+func newTestImportedEndpointSlice(service, cluster string, ready []bool, addresses ...string) *discoveryv1.EndpointSlice {
+	slice := &discoveryv1.EndpointSlice{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      service + "-" + cluster,
+			Namespace: "default",
+			Labels: map[string]string{
+				mcsServiceNameLabel:   service,
+				mcsSourceClusterLabel: cluster,
+			},
+		},
+		AddressType: discoveryv1.AddressTypeIPv4,
+	}
+	for i, address := range addresses {
+		slice.Endpoints = append(slice.Endpoints, discoveryv1.Endpoint{
+			Addresses:  []string{address},
+			Conditions: discoveryv1.EndpointConditions{Ready: &ready[i]},
+		})
+	}
+	return slice
+}
+
+func TestServiceImportSourceCachesImportedEndpointSlicesOnly(t *testing.T) {
+	t.Parallel()
+
+	fakeKubernetesClient := fakeKube.NewSimpleClientset(
+		newTestImportedEndpointSlice("api", "east", []bool{true}, "1.1.1.1"),
+		&discoveryv1.EndpointSlice{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "local-abcde",
+				Namespace: "default",
+				Labels:    map[string]string{discoveryv1.LabelServiceName: "local"},
+			},
+			AddressType: discoveryv1.AddressTypeIPv4,
+		},
+	)
+	fakeDynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{
+			serviceImportGVR: "ServiceImportList",
+		})
+
+	source, err := NewServiceImportSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, "", "", "")
+	require.NoError(t, err)
+
+	slices, err := source.(*serviceImportSource).endpointSliceInformer.Lister().List(labels.Everything())
+	require.NoError(t, err)
+	require.Len(t, slices, 1)
+	require.Equal(t, "api-east", slices[0].Name)
+}
+
+func TestServiceImportSourceEndpoints(t *testing.T) {
+	t.Parallel()
+
+	for _, tt := range []struct {
+		title          string
+		fqdnTemplate   string
+		serviceImports []*unstructured.Unstructured
+		endpointSlices []*discoveryv1.EndpointSlice
+		expected       []*endpoint.Endpoint
+	}{
+		{
+			title:        "targets merged from all exporting clusters",
+			fqdnTemplate: "{{.Name}}.{{.Namespace}}.clusterset.example.com",
+			serviceImports: []*unstructured.Unstructured{
+				newTestServiceImport("api", nil),
+			},
+			endpointSlices: []*discoveryv1.EndpointSlice{
+				newTestImportedEndpointSlice("api", "east", []bool{true, true}, "1.1.1.1", "1.1.1.2"),
+				newTestImportedEndpointSlice("api", "west", []bool{true}, "2.2.2.2"),
+				newTestImportedEndpointSlice("other", "west", []bool{true}, "3.3.3.3"),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("api.default.clusterset.example.com", endpoint.RecordTypeA, "1.1.1.1", "1.1.1.2", "2.2.2.2"),
+			},
+		},
+		{
+			title:        "cluster with unready endpoints is dropped",
+			fqdnTemplate: "{{.Name}}.{{.Namespace}}.clusterset.example.com",
+			serviceImports: []*unstructured.Unstructured{
+				newTestServiceImport("api", nil),
+			},
+			endpointSlices: []*discoveryv1.EndpointSlice{
+				newTestImportedEndpointSlice("api", "east", []bool{true, false}, "1.1.1.1", "1.1.1.2"),
+				newTestImportedEndpointSlice("api", "west", []bool{false}, "2.2.2.2"),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("api.default.clusterset.example.com", endpoint.RecordTypeA, "1.1.1.1"),
+			},
+		},
+		{
+			title: "hostname, target and TTL annotations",
+			serviceImports: []*unstructured.Unstructured{
+				newTestServiceImport("api", map[string]string{
+					hostnameAnnotationKey: "api.example.com",
+					targetAnnotationKey:   "lb.example.net",
+					ttlAnnotationKey:      "60",
+				}),
+			},
+			endpointSlices: []*discoveryv1.EndpointSlice{
+				newTestImportedEndpointSlice("api", "east", []bool{true}, "1.1.1.1"),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpointWithTTL("api.example.com", endpoint.RecordTypeCNAME, 60, "lb.example.net"),
+			},
+		},
+		{
+			title: "no hostname without annotation or template",
+			serviceImports: []*unstructured.Unstructured{
+				newTestServiceImport("api", nil),
+			},
+			endpointSlices: []*discoveryv1.EndpointSlice{
+				newTestImportedEndpointSlice("api", "east", []bool{true}, "1.1.1.1"),
+			},
+			expected: []*endpoint.Endpoint{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			t.Parallel()
+
+			fakeKubernetesClient := fakeKube.NewSimpleClientset()
+			for _, slice := range tt.endpointSlices {
+				_, err := fakeKubernetesClient.DiscoveryV1().EndpointSlices(slice.Namespace).Create(context.Background(), slice, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{
+					serviceImportGVR: "ServiceImportList",
+				})
+			for _, serviceImport := range tt.serviceImports {
+				_, err := fakeDynamicClient.Resource(serviceImportGVR).Namespace(serviceImport.GetNamespace()).Create(context.Background(), serviceImport, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			source, err := NewServiceImportSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, "", "", tt.fqdnTemplate)
+			require.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, tt.expected)
+		})
+	}
+}
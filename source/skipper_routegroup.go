@@ -343,7 +343,7 @@ func (sc *routeGroupSource) endpointsFromRouteGroup(rg *routeGroup) []*endpoint.
 	endpoints := []*endpoint.Endpoint{}
 	ttl, err := getTTLFromAnnotations(rg.Metadata.Annotations)
 	if err != nil {
-		log.Warnf("Failed to get TTL from annotation: %v", err)
+		log.Warnf("Ignoring the TTL annotation of RouteGroup %s/%s: %v", rg.Metadata.Namespace, rg.Metadata.Name, err)
 	}
 
 	targets := getTargetsFromTargetAnnotation(rg.Metadata.Annotations)
@@ -23,16 +23,22 @@ import (
 	"math"
 	"net"
 	"reflect"
+	"regexp"
 	"strconv"
 	"strings"
 	"text/template"
 	"time"
 	"unicode"
 
+	log "github.com/sirupsen/logrus"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
+	kubeinformers "k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
 
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -50,6 +56,8 @@ const (
 	targetAnnotationKey = "external-dns.alpha.kubernetes.io/target"
 	// The annotation used for defining the desired DNS record TTL
 	ttlAnnotationKey = "external-dns.alpha.kubernetes.io/ttl"
+	// The annotation used for opting a resource out of DNS management
+	excludeAnnotationKey = "external-dns.alpha.kubernetes.io/exclude"
 	// The annotation used for switching to the alias record types e. g. AWS Alias records instead of a normal CNAME
 	aliasAnnotationKey = "external-dns.alpha.kubernetes.io/alias"
 	// The annotation used to determine the source of hostnames for ingresses.  This is an optional field - all
@@ -92,6 +100,34 @@ func getTTLFromAnnotations(annotations map[string]string) (endpoint.TTL, error)
 	if !exists {
 		return ttlNotConfigured, nil
 	}
+	return parseTTLAnnotation(ttlAnnotation)
+}
+
+// getTTLForHostnameFromAnnotations returns the TTL of the given hostname. The TTL annotation either
+// holds a single TTL for all hostnames or a comma-separated list with one TTL per hostname of the
+// hostname annotation, e.g. "86400,60". Hostnames which are not part of the hostname annotation
+// get no TTL from such a list.
+func getTTLForHostnameFromAnnotations(annotations map[string]string, hostname string) (endpoint.TTL, error) {
+	ttlNotConfigured := endpoint.TTL(0)
+	ttlAnnotation := annotations[ttlAnnotationKey]
+	if !strings.Contains(ttlAnnotation, ",") {
+		return getTTLFromAnnotations(annotations)
+	}
+	ttlList := strings.Split(strings.Replace(ttlAnnotation, " ", "", -1), ",")
+	hostnameList := getHostnamesFromAnnotations(annotations)
+	if len(ttlList) != len(hostnameList) {
+		return ttlNotConfigured, fmt.Errorf("%d TTL values given for %d hostnames", len(ttlList), len(hostnameList))
+	}
+	for i, name := range hostnameList {
+		if strings.TrimSuffix(name, ".") == strings.TrimSuffix(hostname, ".") {
+			return parseTTLAnnotation(ttlList[i])
+		}
+	}
+	return ttlNotConfigured, nil
+}
+
+func parseTTLAnnotation(ttlAnnotation string) (endpoint.TTL, error) {
+	ttlNotConfigured := endpoint.TTL(0)
 	ttlValue, err := parseTTL(ttlAnnotation)
 	if err != nil {
 		return ttlNotConfigured, fmt.Errorf("\"%v\" is not a valid TTL value", ttlAnnotation)
@@ -122,20 +158,53 @@ type kubeObject interface {
 	metav1.Object
 }
 
+// execTemplate returns the hostnames generated by the FQDN template for obj. Generated names which
+// aren't valid DNS names are skipped with a warning, so they only affect the object they come from.
 func execTemplate(tmpl *template.Template, obj kubeObject) (hostnames []string, err error) {
 	var buf bytes.Buffer
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
 	if err := tmpl.Execute(&buf, obj); err != nil {
-		kind := obj.GetObjectKind().GroupVersionKind().Kind
 		return nil, fmt.Errorf("failed to apply template on %s %s/%s: %w", kind, obj.GetNamespace(), obj.GetName(), err)
 	}
 	for _, name := range strings.Split(buf.String(), ",") {
 		name = strings.TrimFunc(name, unicode.IsSpace)
 		name = strings.TrimSuffix(name, ".")
+		if !isValidHostname(name) {
+			log.Warnf("Skipping invalid hostname %q generated by the FQDN template for %s %s/%s", name, kind, obj.GetNamespace(), obj.GetName())
+			continue
+		}
 		hostnames = append(hostnames, name)
 	}
 	return hostnames, nil
 }
 
+// hostnameRegex matches DNS names made of labels of letters, digits, hyphens and underscores,
+// optionally starting with a wildcard label.
+var hostnameRegex = regexp.MustCompile(`^(\*\.)?[A-Za-z0-9_]([A-Za-z0-9_-]{0,61}[A-Za-z0-9_])?(\.[A-Za-z0-9_]([A-Za-z0-9_-]{0,61}[A-Za-z0-9_])?)*$`)
+
+// isValidHostname returns true if name can be used as the name of a DNS record.
+func isValidHostname(name string) bool {
+	return len(name) <= 253 && hostnameRegex.MatchString(name)
+}
+
+// mergeEndpoints appends the endpoints of additional which aren't part of endpoints yet.
+func mergeEndpoints(endpoints, additional []*endpoint.Endpoint) []*endpoint.Endpoint {
+	for _, ep := range additional {
+		duplicate := false
+		for _, existing := range endpoints {
+			if existing.DNSName == ep.DNSName && existing.RecordType == ep.RecordType &&
+				existing.SetIdentifier == ep.SetIdentifier && existing.Targets.Same(ep.Targets) {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			endpoints = append(endpoints, ep)
+		}
+	}
+	return endpoints
+}
+
 func parseTemplate(fqdnTemplate string) (tmpl *template.Template, err error) {
 	if fqdnTemplate == "" {
 		return nil, nil
@@ -236,13 +305,66 @@ func getTargetsFromTargetAnnotation(annotations map[string]string) endpoint.Targ
 	return targets
 }
 
+// The values of the --prefer-lb-target flag, selecting which addresses of a load balancer become targets.
+const (
+	// LoadBalancerTargetBoth uses both the IP and the hostname of a load balancer
+	LoadBalancerTargetBoth = "both"
+	// LoadBalancerTargetIP uses the IP of a load balancer, or its hostname if it has no IP
+	LoadBalancerTargetIP = "ip"
+	// LoadBalancerTargetHostname uses the hostname of a load balancer, or its IP if it has no hostname
+	LoadBalancerTargetHostname = "hostname"
+)
+
+// targetsFromLoadBalancerStatus returns the addresses of the load balancers of a Service or Ingress
+// according to the given LoadBalancerTarget preference. resource names the Service or Ingress in the
+// warning logged when both IPs and hostnames are returned.
+func targetsFromLoadBalancerStatus(status v1.LoadBalancerStatus, preference string, resource string) endpoint.Targets {
+	var targets endpoint.Targets
+	var hasIP, hasHostname bool
+
+	for _, lb := range status.Ingress {
+		switch {
+		case preference == LoadBalancerTargetIP && lb.IP != "":
+			targets = append(targets, lb.IP)
+		case preference == LoadBalancerTargetHostname && lb.Hostname != "":
+			targets = append(targets, lb.Hostname)
+		default:
+			if lb.IP != "" {
+				targets = append(targets, lb.IP)
+				hasIP = true
+			}
+			if lb.Hostname != "" {
+				targets = append(targets, lb.Hostname)
+				hasHostname = true
+			}
+		}
+	}
+
+	if hasIP && hasHostname {
+		log.Warnf("The load balancers of %s report both IPs and hostnames, which are published as conflicting A and CNAME records of the same name; use --prefer-lb-target=ip or --prefer-lb-target=hostname to publish only one of them", resource)
+	}
+
+	return targets
+}
+
 // suitableType returns the DNS resource record type suitable for the target.
-// In this case type A for IPs and type CNAME for everything else.
+// In this case type A for IPv4 addresses, type AAAA for IPv6 addresses and type CNAME for everything else.
 func suitableType(target string) string {
-	if net.ParseIP(target) != nil {
+	ip := net.ParseIP(target)
+	switch {
+	case ip == nil:
+		return endpoint.RecordTypeCNAME
+	case ip.To4() == nil:
+		return endpoint.RecordTypeAAAA
+	default:
 		return endpoint.RecordTypeA
 	}
-	return endpoint.RecordTypeCNAME
+}
+
+// canonicalIPv6 returns the canonical form of an IPv6 address, e.g. 2001:db8::1 for 2001:0db8:0:0::1,
+// matching the form in which providers return AAAA records so that no changes are planned.
+func canonicalIPv6(target string) string {
+	return net.ParseIP(target).String()
 }
 
 // endpointsForHostname returns the endpoint objects for each host-target combination.
@@ -250,12 +372,15 @@ func endpointsForHostname(hostname string, targets endpoint.Targets, ttl endpoin
 	var endpoints []*endpoint.Endpoint
 
 	var aTargets endpoint.Targets
+	var aaaaTargets endpoint.Targets
 	var cnameTargets endpoint.Targets
 
 	for _, t := range targets {
 		switch suitableType(t) {
 		case endpoint.RecordTypeA:
 			aTargets = append(aTargets, t)
+		case endpoint.RecordTypeAAAA:
+			aaaaTargets = append(aaaaTargets, canonicalIPv6(t))
 		default:
 			cnameTargets = append(cnameTargets, t)
 		}
@@ -274,6 +399,19 @@ func endpointsForHostname(hostname string, targets endpoint.Targets, ttl endpoin
 		endpoints = append(endpoints, epA)
 	}
 
+	if len(aaaaTargets) > 0 {
+		epAAAA := &endpoint.Endpoint{
+			DNSName:          strings.TrimSuffix(hostname, "."),
+			Targets:          aaaaTargets,
+			RecordTTL:        ttl,
+			RecordType:       endpoint.RecordTypeAAAA,
+			Labels:           endpoint.NewLabels(),
+			ProviderSpecific: providerSpecific,
+			SetIdentifier:    setIdentifier,
+		}
+		endpoints = append(endpoints, epAAAA)
+	}
+
 	if len(cnameTargets) > 0 {
 		epCNAME := &endpoint.Endpoint{
 			DNSName:          strings.TrimSuffix(hostname, "."),
@@ -309,6 +447,54 @@ func (fn eventHandlerFunc) OnAdd(obj interface{})               { fn() }
 func (fn eventHandlerFunc) OnUpdate(oldObj, newObj interface{}) { fn() }
 func (fn eventHandlerFunc) OnDelete(obj interface{})            { fn() }
 
+// unlessExcludedEventHandlerFunc is an eventHandlerFunc which isn't called for changes of
+// resources which are excluded from DNS management before and after the change.
+type unlessExcludedEventHandlerFunc func()
+
+func (fn unlessExcludedEventHandlerFunc) OnAdd(obj interface{}) {
+	if !isExcludedObject(obj) {
+		fn()
+	}
+}
+
+func (fn unlessExcludedEventHandlerFunc) OnUpdate(oldObj, newObj interface{}) {
+	if !isExcludedObject(oldObj) || !isExcludedObject(newObj) {
+		fn()
+	}
+}
+
+func (fn unlessExcludedEventHandlerFunc) OnDelete(obj interface{}) {
+	if !isExcludedObject(obj) {
+		fn()
+	}
+}
+
+// isExcluded returns true if the annotations opt a resource out of DNS management.
+func isExcluded(annotations map[string]string) bool {
+	return annotations[excludeAnnotationKey] == "true"
+}
+
+func isExcludedObject(obj interface{}) bool {
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return false
+	}
+	return isExcluded(m.GetAnnotations())
+}
+
+// newLabelFilteredInformerFactory returns an informer factory for the given namespace whose informers
+// only list and watch the objects matching labelSelector, so that the others never reach the cache.
+func newLabelFilteredInformerFactory(client kubernetes.Interface, namespace string, labelSelector labels.Selector) kubeinformers.SharedInformerFactory {
+	opts := []kubeinformers.SharedInformerOption{kubeinformers.WithNamespace(namespace)}
+	if labelSelector != nil && !labelSelector.Empty() {
+		lbls := labelSelector.String()
+		opts = append(opts, kubeinformers.WithTweakListOptions(func(o *metav1.ListOptions) {
+			o.LabelSelector = lbls
+		}))
+	}
+	return kubeinformers.NewSharedInformerFactoryWithOptions(client, 0, opts...)
+}
+
 type informerFactory interface {
 	WaitForCacheSync(stopCh <-chan struct{}) map[reflect.Type]bool
 }
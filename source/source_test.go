@@ -18,9 +18,14 @@ package source
 
 import (
 	"fmt"
+	"strings"
 	"testing"
 
+	log "github.com/sirupsen/logrus"
+	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"sigs.k8s.io/external-dns/endpoint"
 )
@@ -89,11 +94,145 @@ func TestGetTTLFromAnnotations(t *testing.T) {
 	}
 }
 
+func TestGetTTLForHostnameFromAnnotations(t *testing.T) {
+	for _, tc := range []struct {
+		title       string
+		annotations map[string]string
+		hostname    string
+		expectedTTL endpoint.TTL
+		expectedErr error
+	}{
+		{
+			title:       "single TTL applies to all hostnames",
+			annotations: map[string]string{hostnameAnnotationKey: "a.example.org,b.example.org", ttlAnnotationKey: "60"},
+			hostname:    "b.example.org",
+			expectedTTL: endpoint.TTL(60),
+		},
+		{
+			title:       "TTL list is matched with the hostnames",
+			annotations: map[string]string{hostnameAnnotationKey: "a.example.org,b.example.org.", ttlAnnotationKey: "86400, 10m"},
+			hostname:    "b.example.org",
+			expectedTTL: endpoint.TTL(600),
+		},
+		{
+			title:       "TTL list does not apply to other hostnames",
+			annotations: map[string]string{hostnameAnnotationKey: "a.example.org,b.example.org", ttlAnnotationKey: "86400,60"},
+			hostname:    "c.example.org",
+			expectedTTL: endpoint.TTL(0),
+		},
+		{
+			title:       "TTL list with fewer values than hostnames",
+			annotations: map[string]string{hostnameAnnotationKey: "a.example.org,b.example.org,c.example.org", ttlAnnotationKey: "86400,60"},
+			hostname:    "a.example.org",
+			expectedTTL: endpoint.TTL(0),
+			expectedErr: fmt.Errorf("2 TTL values given for 3 hostnames"),
+		},
+		{
+			title:       "TTL list with an invalid value",
+			annotations: map[string]string{hostnameAnnotationKey: "a.example.org,b.example.org", ttlAnnotationKey: "86400,foo"},
+			hostname:    "b.example.org",
+			expectedTTL: endpoint.TTL(0),
+			expectedErr: fmt.Errorf("\"foo\" is not a valid TTL value"),
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			ttl, err := getTTLForHostnameFromAnnotations(tc.annotations, tc.hostname)
+			assert.Equal(t, tc.expectedTTL, ttl)
+			assert.Equal(t, tc.expectedErr, err)
+		})
+	}
+}
+
+func TestTargetsFromLoadBalancerStatus(t *testing.T) {
+	status := v1.LoadBalancerStatus{
+		Ingress: []v1.LoadBalancerIngress{
+			{IP: "1.2.3.4", Hostname: "lb1.example.com"},
+			{IP: "5.6.7.8"},
+			{Hostname: "lb2.example.com"},
+		},
+	}
+
+	for _, tc := range []struct {
+		preference string
+		expected   endpoint.Targets
+	}{
+		{LoadBalancerTargetBoth, endpoint.Targets{"1.2.3.4", "lb1.example.com", "5.6.7.8", "lb2.example.com"}},
+		{LoadBalancerTargetIP, endpoint.Targets{"1.2.3.4", "5.6.7.8", "lb2.example.com"}},
+		{LoadBalancerTargetHostname, endpoint.Targets{"lb1.example.com", "5.6.7.8", "lb2.example.com"}},
+	} {
+		t.Run(tc.preference, func(t *testing.T) {
+			assert.Equal(t, tc.expected, targetsFromLoadBalancerStatus(status, tc.preference, "service default/foo"))
+		})
+	}
+}
+
+func TestTargetsFromLoadBalancerStatusWarnsOfMixedTargets(t *testing.T) {
+	hook := logtest.NewGlobal()
+	defer hook.Reset()
+
+	warnings := func(resource string) int {
+		count := 0
+		for _, entry := range hook.AllEntries() {
+			if entry.Level == log.WarnLevel && strings.Contains(entry.Message, resource) {
+				count++
+			}
+		}
+		return count
+	}
+
+	targetsFromLoadBalancerStatus(v1.LoadBalancerStatus{
+		Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4", Hostname: "lb.example.com"}},
+	}, LoadBalancerTargetBoth, "service default/mixed")
+	assert.Equal(t, 1, warnings("service default/mixed"), "should warn once for a resource with both IPs and hostnames")
+
+	targetsFromLoadBalancerStatus(v1.LoadBalancerStatus{
+		Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4", Hostname: "lb.example.com"}},
+	}, LoadBalancerTargetIP, "service default/preferred")
+	targetsFromLoadBalancerStatus(v1.LoadBalancerStatus{
+		Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}, {IP: "5.6.7.8"}},
+	}, LoadBalancerTargetBoth, "service default/ips")
+	assert.Zero(t, warnings("service default/preferred"), "should not warn with a preference")
+	assert.Zero(t, warnings("service default/ips"), "should not warn with IPs only")
+}
+
+func TestUnlessExcludedEventHandlerFunc(t *testing.T) {
+	included := &v1.Service{}
+	excluded := &v1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: map[string]string{excludeAnnotationKey: "true"},
+		},
+	}
+
+	calls := 0
+	handler := unlessExcludedEventHandlerFunc(func() { calls++ })
+
+	handler.OnAdd(excluded)
+	handler.OnUpdate(excluded, excluded)
+	handler.OnDelete(excluded)
+	assert.Equal(t, 0, calls, "changes of excluded resources must not trigger a sync")
+
+	handler.OnAdd(included)
+	handler.OnUpdate(included, excluded)
+	handler.OnUpdate(excluded, included)
+	handler.OnDelete(included)
+	assert.Equal(t, 4, calls)
+}
+
+func TestIsValidHostname(t *testing.T) {
+	for _, name := range []string{"example.org", "foo-bar.example.org", "*.example.org", "_sip._tcp.example.org", "localhost"} {
+		assert.True(t, isValidHostname(name), name)
+	}
+	for _, name := range []string{"", "foo..example.org", "-foo.example.org", "foo bar.example.org", "foo.*.example.org", "foo/bar.example.org"} {
+		assert.False(t, isValidHostname(name), name)
+	}
+}
+
 func TestSuitableType(t *testing.T) {
 	for _, tc := range []struct {
 		target, recordType, expected string
 	}{
 		{"8.8.8.8", "", "A"},
+		{"2001:db8::1", "", "AAAA"},
 		{"foo.example.org", "", "CNAME"},
 		{"bar.eu-central-1.elb.amazonaws.com", "", "CNAME"},
 	} {
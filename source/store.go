@@ -30,8 +30,10 @@ import (
 	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	istioclient "istio.io/client-go/pkg/clientset/versioned"
+	v1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/client-go/dynamic"
+	coreinformers "k8s.io/client-go/informers/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd"
@@ -44,6 +46,7 @@ var ErrSourceNotFound = errors.New("source not found")
 // Config holds shared configuration options for all Sources.
 type Config struct {
 	Namespace                      string
+	Namespaces                     []string
 	AnnotationFilter               string
 	LabelFilter                    labels.Selector
 	FQDNTemplate                   string
@@ -51,6 +54,15 @@ type Config struct {
 	IgnoreHostnameAnnotation       bool
 	IgnoreIngressTLSSpec           bool
 	IgnoreIngressRulesSpec         bool
+	IngressClassNames              []string
+	NodeAddressPreference          string
+	NodePortLabelFilter            labels.Selector
+	NodeLabelFilter                labels.Selector
+	PodLabelFilter                 labels.Selector
+	PreferLBTarget                 string
+	PodFQDNTemplate                string
+	ServiceImportFQDNTemplate      string
+	PodSourceTTL                   time.Duration
 	GatewayNamespace               string
 	GatewayLabelFilter             string
 	Compatibility                  string
@@ -69,6 +81,8 @@ type Config struct {
 	ContourLoadBalancerService     string
 	GlooNamespace                  string
 	SkipperRouteGroupVersion       string
+	TraefikService                 string
+	KongProxyService               string
 	RequestTimeout                 time.Duration
 	DefaultTargets                 []string
 	OCPRouterName                  string
@@ -186,11 +200,33 @@ func (p *SingletonClientGenerator) OpenShiftClient() (openshift.Interface, error
 	return p.openshiftClient, err
 }
 
+// namespacedSources are the sources whose resources are limited to the namespaces of the Config.
+var namespacedSources = map[string]struct{}{
+	"service":              {},
+	"ingress":              {},
+	"pod":                  {},
+	"gateway-httproute":    {},
+	"gateway-tlsroute":     {},
+	"gateway-tcproute":     {},
+	"gateway-udproute":     {},
+	"istio-gateway":        {},
+	"istio-virtualservice": {},
+	"ambassador-host":      {},
+	"contour-httpproxy":    {},
+	"openshift-route":      {},
+	"crd":                  {},
+	"skipper-routegroup":   {},
+	"kong-tcpingress":      {},
+	"kong-udpingress":      {},
+	"serviceimport":        {},
+	"traefik-proxy":        {},
+}
+
 // ByNames returns multiple Sources given multiple names.
 func ByNames(ctx context.Context, p ClientGenerator, names []string, cfg *Config) ([]Source, error) {
 	sources := []Source{}
 	for _, name := range names {
-		source, err := BuildWithConfig(ctx, name, p, cfg)
+		source, err := buildForNamespaces(ctx, name, p, cfg)
 		if err != nil {
 			return nil, err
 		}
@@ -200,6 +236,69 @@ func ByNames(ctx context.Context, p ClientGenerator, names []string, cfg *Config
 	return sources, nil
 }
 
+// buildForNamespaces generates a Source implementation for each of the namespaces of the shared config,
+// so that only the resources of those namespaces are listed and watched, and merges their endpoints.
+// Without namespaces, or for a source which isn't namespaced, a single Source is generated.
+func buildForNamespaces(ctx context.Context, source string, p ClientGenerator, cfg *Config) (Source, error) {
+	namespaces := splitNamespaces(cfg.Namespaces)
+	if _, ok := namespacedSources[source]; !ok || len(namespaces) == 0 {
+		return BuildWithConfig(ctx, source, p, cfg)
+	}
+
+	build := BuildWithConfig
+	if source == "service" && serviceSourceNeedsNodes(cfg.ServiceTypeFilter, cfg.Compatibility) {
+		// Nodes aren't namespaced, the services of all the namespaces share a single node informer.
+		client, err := p.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		nodeInformer, err := newNodeInformer(ctx, client)
+		if err != nil {
+			return nil, err
+		}
+		build = func(ctx context.Context, _ string, _ ClientGenerator, cfg *Config) (Source, error) {
+			return buildServiceSource(ctx, client, nodeInformer, cfg)
+		}
+	}
+
+	children := make([]Source, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		nsCfg := *cfg
+		nsCfg.Namespace = namespace
+		child, err := build(ctx, source, p, &nsCfg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to create %s source for namespace %q, check that it exists and ExternalDNS is allowed to list and watch its resources", source, namespace)
+		}
+		children = append(children, child)
+	}
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return NewMultiSource(children, nil), nil
+}
+
+// splitNamespaces returns the distinct namespaces of a list whose values may be comma separated.
+func splitNamespaces(values []string) []string {
+	var namespaces []string
+	seen := map[string]struct{}{}
+	for _, value := range values {
+		for _, namespace := range strings.Split(value, ",") {
+			namespace = strings.TrimSpace(namespace)
+			if _, ok := seen[namespace]; ok || namespace == "" {
+				continue
+			}
+			seen[namespace] = struct{}{}
+			namespaces = append(namespaces, namespace)
+		}
+	}
+	return namespaces
+}
+
+// buildServiceSource generates a service Source reading the nodes from the given informer, if any.
+func buildServiceSource(ctx context.Context, client kubernetes.Interface, nodeInformer coreinformers.NodeInformer, cfg *Config) (Source, error) {
+	return newServiceSource(ctx, client, nodeInformer, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.Compatibility, cfg.PublishInternal, cfg.PublishHostIP, cfg.AlwaysPublishNotReadyAddresses, cfg.ServiceTypeFilter, cfg.IgnoreHostnameAnnotation, cfg.LabelFilter, v1.NodeAddressType(cfg.NodeAddressPreference), cfg.NodePortLabelFilter, cfg.PreferLBTarget)
+}
+
 // BuildWithConfig allows to generate a Source implementation from the shared config
 func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg *Config) (Source, error) {
 	switch source {
@@ -208,25 +307,25 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 		if err != nil {
 			return nil, err
 		}
-		return NewNodeSource(ctx, client, cfg.AnnotationFilter, cfg.FQDNTemplate)
+		return NewNodeSource(ctx, client, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.NodeLabelFilter, v1.NodeAddressType(cfg.NodeAddressPreference))
 	case "service":
 		client, err := p.KubeClient()
 		if err != nil {
 			return nil, err
 		}
-		return NewServiceSource(ctx, client, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.Compatibility, cfg.PublishInternal, cfg.PublishHostIP, cfg.AlwaysPublishNotReadyAddresses, cfg.ServiceTypeFilter, cfg.IgnoreHostnameAnnotation, cfg.LabelFilter)
+		return buildServiceSource(ctx, client, nil, cfg)
 	case "ingress":
 		client, err := p.KubeClient()
 		if err != nil {
 			return nil, err
 		}
-		return NewIngressSource(ctx, client, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.IgnoreHostnameAnnotation, cfg.IgnoreIngressTLSSpec, cfg.IgnoreIngressRulesSpec, cfg.LabelFilter)
+		return NewIngressSource(ctx, client, cfg.Namespace, cfg.AnnotationFilter, cfg.FQDNTemplate, cfg.CombineFQDNAndAnnotation, cfg.IgnoreHostnameAnnotation, cfg.IgnoreIngressTLSSpec, cfg.IgnoreIngressRulesSpec, cfg.LabelFilter, cfg.IngressClassNames, cfg.PreferLBTarget)
 	case "pod":
 		client, err := p.KubeClient()
 		if err != nil {
 			return nil, err
 		}
-		return NewPodSource(ctx, client, cfg.Namespace, cfg.Compatibility)
+		return NewPodSource(ctx, client, cfg.Namespace, cfg.Compatibility, cfg.PodFQDNTemplate, cfg.PodLabelFilter, cfg.PodSourceTTL)
 	case "gateway-httproute":
 		return NewGatewayHTTPRouteSource(p, cfg)
 	case "gateway-tlsroute":
@@ -327,7 +426,37 @@ func BuildWithConfig(ctx context.Context, source string, p ClientGenerator, cfg
 		if err != nil {
 			return nil, err
 		}
-		return NewKongTCPIngressSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter)
+		return NewKongTCPIngressSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.KongProxyService, cfg.PreferLBTarget)
+	case "kong-udpingress":
+		kubernetesClient, err := p.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		dynamicClient, err := p.DynamicKubernetesClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewKongUDPIngressSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.KongProxyService, cfg.PreferLBTarget)
+	case "serviceimport":
+		kubernetesClient, err := p.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		dynamicClient, err := p.DynamicKubernetesClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewServiceImportSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.ServiceImportFQDNTemplate)
+	case "traefik-proxy":
+		kubernetesClient, err := p.KubeClient()
+		if err != nil {
+			return nil, err
+		}
+		dynamicClient, err := p.DynamicKubernetesClient()
+		if err != nil {
+			return nil, err
+		}
+		return NewTraefikSource(ctx, dynamicClient, kubernetesClient, cfg.Namespace, cfg.AnnotationFilter, cfg.IgnoreHostnameAnnotation, cfg.TraefikService, cfg.PreferLBTarget)
 	}
 	return nil, ErrSourceNotFound
 }
@@ -27,6 +27,9 @@ import (
 	"github.com/stretchr/testify/suite"
 	istioclient "istio.io/client-go/pkg/clientset/versioned"
 	istiofake "istio.io/client-go/pkg/clientset/versioned/fake"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/client-go/dynamic"
@@ -181,6 +184,93 @@ func (suite *ByNamesTestSuite) TestIstioClientFails() {
 	suite.Error(err, "should return an error if contour client cannot be created")
 }
 
+func (suite *ByNamesTestSuite) TestMultipleNamespaces() {
+	kubeClient := fakeKube.NewSimpleClientset()
+	for _, namespace := range []string{"a", "b", "c"} {
+		_, err := kubeClient.CoreV1().Services(namespace).Create(context.Background(), &v1.Service{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        "foo",
+				Annotations: map[string]string{hostnameAnnotationKey: namespace + ".example.org"},
+			},
+			Spec: v1.ServiceSpec{Type: v1.ServiceTypeLoadBalancer},
+			Status: v1.ServiceStatus{
+				LoadBalancer: v1.LoadBalancerStatus{Ingress: []v1.LoadBalancerIngress{{IP: "1.2.3.4"}}},
+			},
+		}, metav1.CreateOptions{})
+		suite.NoError(err)
+	}
+	mockClientGenerator := new(MockClientGenerator)
+	mockClientGenerator.On("KubeClient").Return(kubeClient, nil)
+
+	cfg := &Config{Namespaces: []string{"a, b", "b"}, LabelFilter: labels.Everything()}
+	sources, err := ByNames(context.TODO(), mockClientGenerator, []string{"service", "fake"}, cfg)
+	suite.NoError(err, "should not generate errors")
+	suite.Len(sources, 2, "should generate a source for each name")
+	suite.IsType(&multiSource{}, sources[0], "should merge the sources of each namespace")
+	suite.Len(sources[0].(*multiSource).children, 2, "should generate a source for each distinct namespace")
+	suite.IsType(&fakeSource{}, sources[1], "should not generate a source for each namespace if not namespaced")
+	children := sources[0].(*multiSource).children
+	suite.NotNil(children[0].(*serviceSource).nodeInformer)
+	suite.Same(children[0].(*serviceSource).nodeInformer, children[1].(*serviceSource).nodeInformer, "should share the node informer")
+
+	endpoints, err := sources[0].Endpoints(context.Background())
+	suite.NoError(err)
+	var names []string
+	for _, ep := range endpoints {
+		names = append(names, ep.DNSName)
+	}
+	suite.ElementsMatch([]string{"a.example.org", "b.example.org"}, names, "should only return endpoints of the given namespaces")
+}
+
+func (suite *ByNamesTestSuite) TestMultipleNamespacesKubeClientFails() {
+	mockClientGenerator := new(MockClientGenerator)
+	mockClientGenerator.On("KubeClient").Return(nil, errors.New("foo"))
+
+	_, err := ByNames(context.TODO(), mockClientGenerator, []string{"service"}, &Config{Namespaces: []string{"a", "b"}, ServiceTypeFilter: []string{"LoadBalancer"}})
+	suite.ErrorContains(err, `namespace "a"`, "should name the namespace of the failing source")
+}
+
+func (suite *ByNamesTestSuite) TestMultipleNamespacesWithoutNodes() {
+	mockClientGenerator := new(MockClientGenerator)
+	mockClientGenerator.On("KubeClient").Return(fakeKube.NewSimpleClientset(), nil)
+
+	cfg := &Config{Namespaces: []string{"a", "b"}, ServiceTypeFilter: []string{"LoadBalancer"}, LabelFilter: labels.Everything()}
+	sources, err := ByNames(context.TODO(), mockClientGenerator, []string{"service"}, cfg)
+	suite.NoError(err, "should not generate errors")
+	for _, child := range sources[0].(*multiSource).children {
+		suite.Nil(child.(*serviceSource).nodeInformer, "should not watch the nodes of the cluster")
+	}
+}
+
+func (suite *ByNamesTestSuite) TestLabelFilterIgnoresNodes() {
+	kubeClient := fakeKube.NewSimpleClientset(&v1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: "node1", Labels: map[string]string{"node-role.kubernetes.io/worker": ""}},
+		Status: v1.NodeStatus{
+			Addresses: []v1.NodeAddress{{Type: v1.NodeExternalIP, Address: "1.2.3.4"}},
+		},
+	})
+	mockClientGenerator := new(MockClientGenerator)
+	mockClientGenerator.On("KubeClient").Return(kubeClient, nil)
+
+	labelFilter, err := labels.Parse("app=foo")
+	suite.NoError(err)
+	cfg := &Config{LabelFilter: labelFilter}
+	sources, err := ByNames(context.TODO(), mockClientGenerator, []string{"node"}, cfg)
+	suite.NoError(err, "should not generate errors")
+	endpoints, err := sources[0].Endpoints(context.Background())
+	suite.NoError(err)
+	suite.Len(endpoints, 1, "should not filter the nodes by the label filter")
+
+	cfg.NodeLabelFilter, err = labels.Parse("node-role.kubernetes.io/control-plane")
+	suite.NoError(err)
+	sources, err = ByNames(context.TODO(), mockClientGenerator, []string{"node"}, cfg)
+	suite.NoError(err, "should not generate errors")
+	endpoints, err = sources[0].Endpoints(context.Background())
+	suite.NoError(err)
+	suite.Empty(endpoints, "should filter the nodes by the node label filter")
+}
+
 func TestByNames(t *testing.T) {
 	suite.Run(t, new(ByNamesTestSuite))
 }
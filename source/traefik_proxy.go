@@ -0,0 +1,279 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/dynamic/dynamicinformer"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+var (
+	traefikIngressRouteGVR = schema.GroupVersionResource{
+		Group:    "traefik.containo.us",
+		Version:  "v1alpha1",
+		Resource: "ingressroutes",
+	}
+	traefikIngressRouteTCPGVR = schema.GroupVersionResource{
+		Group:    "traefik.containo.us",
+		Version:  "v1alpha1",
+		Resource: "ingressroutetcps",
+	}
+
+	// traefikHostMatcher matches the Host() matchers of a HTTP router rule, but not HostRegexp()
+	traefikHostMatcher = regexp.MustCompile(`\bHost\(([^)]*)\)`)
+	// traefikHostSNIMatcher matches the HostSNI() matchers of a TCP router rule
+	traefikHostSNIMatcher = regexp.MustCompile(`\bHostSNI\(([^)]*)\)`)
+	// traefikHostRegexpMatcher matches the HostRegexp() matchers of a HTTP router rule
+	traefikHostRegexpMatcher = regexp.MustCompile(`\bHostRegexp\(`)
+	// traefikMatcherValue matches a single quoted value of a matcher
+	traefikMatcherValue = regexp.MustCompile("`([^`]*)`|\"([^\"]*)\"")
+)
+
+// traefikProxySource is an implementation of Source for Traefik IngressRoute and IngressRouteTCP objects.
+// The hostnames are taken from the Host() and HostSNI() matchers of their routes, the targets from
+// the target annotation or else from the load balancer of the Traefik Service.
+type traefikProxySource struct {
+	annotationFilter         string
+	ignoreHostnameAnnotation bool
+	namespace                string
+	traefikService           *proxyService
+	ingressRouteInformer     informers.GenericInformer
+	ingressRouteTCPInformer  informers.GenericInformer
+	dynamicKubeClient        dynamic.Interface
+}
+
+// NewTraefikSource creates a new traefikProxySource with the given config. traefikService is the
+// namespace/name of the Service exposing Traefik, preferLBTarget the LoadBalancerTarget preference
+// for its load balancer addresses.
+func NewTraefikSource(ctx context.Context, dynamicKubeClient dynamic.Interface, kubeClient kubernetes.Interface, namespace string, annotationFilter string, ignoreHostnameAnnotation bool, traefikService string, preferLBTarget string) (Source, error) {
+	if traefikService == "" {
+		log.Warn("No Traefik service is set with --traefik-service, only IngressRoutes and IngressRouteTCPs with a target annotation are published")
+	}
+	service, err := newProxyService(ctx, kubeClient, "Traefik", traefikService, preferLBTarget)
+	if err != nil {
+		return nil, err
+	}
+
+	// Use shared informer to listen for add/update/delete of IngressRoutes in the specified namespace.
+	// Set resync period to 0, to prevent processing when nothing has changed.
+	informerFactory := dynamicinformer.NewFilteredDynamicSharedInformerFactory(dynamicKubeClient, 0, namespace, nil)
+	ingressRouteInformer := informerFactory.ForResource(traefikIngressRouteGVR)
+	ingressRouteTCPInformer := informerFactory.ForResource(traefikIngressRouteTCPGVR)
+
+	// Add default resource event handlers to properly initialize informers.
+	ingressRouteInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {},
+		},
+	)
+	ingressRouteTCPInformer.Informer().AddEventHandler(
+		cache.ResourceEventHandlerFuncs{
+			AddFunc: func(obj interface{}) {},
+		},
+	)
+
+	informerFactory.Start(ctx.Done())
+
+	// wait for the local cache to be populated.
+	if err := waitForDynamicCacheSync(context.Background(), informerFactory); err != nil {
+		return nil, err
+	}
+
+	return &traefikProxySource{
+		annotationFilter:         annotationFilter,
+		ignoreHostnameAnnotation: ignoreHostnameAnnotation,
+		namespace:                namespace,
+		traefikService:           service,
+		ingressRouteInformer:     ingressRouteInformer,
+		ingressRouteTCPInformer:  ingressRouteTCPInformer,
+		dynamicKubeClient:        dynamicKubeClient,
+	}, nil
+}
+
+// Endpoints returns endpoint objects for each host-target combination that should be processed.
+// Retrieves all IngressRoutes and IngressRouteTCPs in the source's namespace(s).
+func (ts *traefikProxySource) Endpoints(ctx context.Context) ([]*endpoint.Endpoint, error) {
+	serviceTargets := ts.traefikService.targets()
+
+	routes, err := ts.list(ts.ingressRouteInformer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list IngressRoutes")
+	}
+	tcpRoutes, err := ts.list(ts.ingressRouteTCPInformer)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list IngressRouteTCPs")
+	}
+
+	var endpoints []*endpoint.Endpoint
+	for _, route := range routes {
+		eps, err := ts.endpointsFromRoute(route, "ingressroute", traefikHostMatcher, serviceTargets)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, eps...)
+	}
+	for _, route := range tcpRoutes {
+		eps, err := ts.endpointsFromRoute(route, "ingressroutetcp", traefikHostSNIMatcher, serviceTargets)
+		if err != nil {
+			return nil, err
+		}
+		endpoints = append(endpoints, eps...)
+	}
+
+	for _, ep := range endpoints {
+		sort.Sort(ep.Targets)
+	}
+
+	return endpoints, nil
+}
+
+// list returns the objects of the given informer which match the annotation filter.
+func (ts *traefikProxySource) list(informer informers.GenericInformer) ([]*unstructured.Unstructured, error) {
+	objs, err := informer.Lister().ByNamespace(ts.namespace).List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+
+	labelSelector, err := metav1.ParseToLabelSelector(ts.annotationFilter)
+	if err != nil {
+		return nil, err
+	}
+	selector, err := metav1.LabelSelectorAsSelector(labelSelector)
+	if err != nil {
+		return nil, err
+	}
+
+	var filteredList []*unstructured.Unstructured
+	for _, obj := range objs {
+		u, ok := obj.(*unstructured.Unstructured)
+		if !ok {
+			return nil, errors.New("could not convert")
+		}
+		// include the route if its annotations match the selector
+		if selector.Empty() || selector.Matches(labels.Set(u.GetAnnotations())) {
+			filteredList = append(filteredList, u)
+		}
+	}
+	return filteredList, nil
+}
+
+// endpointsFromRoute extracts the endpoints from an IngressRoute or IngressRouteTCP object.
+func (ts *traefikProxySource) endpointsFromRoute(route *unstructured.Unstructured, kind string, hostMatcher *regexp.Regexp, serviceTargets endpoint.Targets) ([]*endpoint.Endpoint, error) {
+	fullname := fmt.Sprintf("%s/%s", route.GetNamespace(), route.GetName())
+	annotations := route.GetAnnotations()
+
+	// Check controller annotation to see if we are responsible.
+	if controller, ok := annotations[controllerAnnotationKey]; ok && controller != controllerAnnotationValue {
+		log.Debugf("Skipping %s %s because controller value does not match, found: %s, required: %s",
+			kind, fullname, controller, controllerAnnotationValue)
+		return nil, nil
+	}
+
+	targets := getTargetsFromTargetAnnotation(annotations)
+	if len(targets) == 0 {
+		targets = serviceTargets
+	}
+	if len(targets) == 0 {
+		log.Debugf("No targets could be found for %s %s", kind, fullname)
+		return nil, nil
+	}
+
+	ttl, err := getTTLFromAnnotations(annotations)
+	if err != nil {
+		log.Warnf("Ignoring the TTL annotation of %s %s: %v", kind, fullname, err)
+	}
+	providerSpecific, setIdentifier := getProviderSpecificAnnotations(annotations)
+
+	var hostnames []string
+	if !ts.ignoreHostnameAnnotation {
+		hostnames = append(hostnames, getHostnamesFromAnnotations(annotations)...)
+	}
+
+	routes, _, err := unstructured.NestedSlice(route.Object, "spec", "routes")
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get routes of %s %s", kind, fullname)
+	}
+	for _, r := range routes {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			log.Warnf("Skipping malformed route of %s %s", kind, fullname)
+			continue
+		}
+		rule, ok := m["match"].(string)
+		if !ok {
+			continue
+		}
+		if traefikHostRegexpMatcher.MatchString(rule) {
+			log.Warnf("Skipping HostRegexp() matchers of %s %s, only Host() matchers are supported", kind, fullname)
+		}
+		hostnames = append(hostnames, traefikMatcherHosts(hostMatcher, rule)...)
+	}
+
+	var endpoints []*endpoint.Endpoint
+	for _, hostname := range hostnames {
+		endpoints = append(endpoints, endpointsForHostname(hostname, targets, ttl, providerSpecific, setIdentifier)...)
+	}
+	for _, ep := range endpoints {
+		ep.Labels[endpoint.ResourceLabelKey] = fmt.Sprintf("%s/%s", kind, fullname)
+	}
+
+	log.Debugf("Endpoints generated from %s %s: %v", kind, fullname, endpoints)
+	return endpoints, nil
+}
+
+// traefikMatcherHosts returns the hostnames of all matchers of a rule, e.g.
+// Host(`a.example.com`, `b.example.com`) || Host(`c.example.com`). The catch-all
+// HostSNI(`*`) is skipped.
+func traefikMatcherHosts(matcher *regexp.Regexp, rule string) []string {
+	var hosts []string
+	for _, m := range matcher.FindAllStringSubmatch(rule, -1) {
+		for _, v := range traefikMatcherValue.FindAllStringSubmatch(m[1], -1) {
+			host := v[1] + v[2]
+			if host == "" || host == "*" {
+				continue
+			}
+			hosts = append(hosts, host)
+		}
+	}
+	return hosts
+}
+
+func (ts *traefikProxySource) AddEventHandler(ctx context.Context, handler func()) {
+	log.Debug("Adding event handler for IngressRoute and IngressRouteTCP")
+
+	// Right now there is no way to remove event handler from informer, see:
+	// https://github.com/kubernetes/kubernetes/issues/79610
+	ts.ingressRouteInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	ts.ingressRouteTCPInformer.Informer().AddEventHandler(eventHandlerFunc(handler))
+	ts.traefikService.addEventHandler(handler)
+}
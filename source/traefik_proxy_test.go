@@ -0,0 +1,226 @@
+/*
+Copyright 2022 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package source
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	fakeDynamic "k8s.io/client-go/dynamic/fake"
+	fakeKube "k8s.io/client-go/kubernetes/fake"
+
+	"sigs.k8s.io/external-dns/endpoint"
+)
+
+// This is a compile-time validation that traefikProxySource is a Source.
+var _ Source = &traefikProxySource{}
+
+const defaultTraefikNamespace = "traefik"
+
+func newTraefikRoute(kind, name string, annotations map[string]string, rules ...string) *unstructured.Unstructured {
+	routes := make([]interface{}, 0, len(rules))
+	for _, rule := range rules {
+		routes = append(routes, map[string]interface{}{"match": rule})
+	}
+	route := &unstructured.Unstructured{
+		Object: map[string]interface{}{
+			"apiVersion": traefikIngressRouteGVR.GroupVersion().String(),
+			"kind":       kind,
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": defaultTraefikNamespace,
+			},
+			"spec": map[string]interface{}{
+				"routes": routes,
+			},
+		},
+	}
+	route.SetAnnotations(annotations)
+	return route
+}
+
+func TestTraefikProxySourceEndpoints(t *testing.T) {
+	t.Parallel()
+
+	traefikService := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "traefik",
+			Namespace: defaultTraefikNamespace,
+		},
+		Status: corev1.ServiceStatus{
+			LoadBalancer: corev1.LoadBalancerStatus{
+				Ingress: []corev1.LoadBalancerIngress{
+					{IP: "1.2.3.4"},
+					{Hostname: "lb.example.com"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range []struct {
+		title          string
+		traefikService string
+		ingressRoutes  []*unstructured.Unstructured
+		tcpRoutes      []*unstructured.Unstructured
+		expected       []*endpoint.Endpoint
+	}{
+		{
+			title:          "multiple Host() values and matchers in one rule",
+			traefikService: "traefik/traefik",
+			ingressRoutes: []*unstructured.Unstructured{
+				newTraefikRoute("IngressRoute", "web", nil,
+					"Host(`a.example.com`, `b.example.com`) || (Host(`c.example.com`) && PathPrefix(`/api`))",
+					"Host(\"d.example.com\")",
+				),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("a.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+				newTestEndpoint("a.example.com", endpoint.RecordTypeCNAME, "lb.example.com"),
+				newTestEndpoint("b.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+				newTestEndpoint("b.example.com", endpoint.RecordTypeCNAME, "lb.example.com"),
+				newTestEndpoint("c.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+				newTestEndpoint("c.example.com", endpoint.RecordTypeCNAME, "lb.example.com"),
+				newTestEndpoint("d.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+				newTestEndpoint("d.example.com", endpoint.RecordTypeCNAME, "lb.example.com"),
+			},
+		},
+		{
+			title: "HostRegexp() matchers are skipped",
+			ingressRoutes: []*unstructured.Unstructured{
+				newTraefikRoute("IngressRoute", "regexp", map[string]string{
+					targetAnnotationKey: "5.6.7.8",
+				},
+					"HostRegexp(`{subdomain:[a-z]+}.example.com`)",
+					"HostRegexp(`{any:.+}.example.org`) || Host(`e.example.com`)",
+				),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("e.example.com", endpoint.RecordTypeA, "5.6.7.8"),
+			},
+		},
+		{
+			title:          "HostSNI() matchers of IngressRouteTCP, the catch-all is skipped",
+			traefikService: "traefik/traefik",
+			tcpRoutes: []*unstructured.Unstructured{
+				newTraefikRoute("IngressRouteTCP", "tcp", map[string]string{
+					ttlAnnotationKey: "300",
+				},
+					"HostSNI(`db.example.com`)",
+					"HostSNI(`*`)",
+				),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpointWithTTL("db.example.com", endpoint.RecordTypeA, 300, "1.2.3.4"),
+				newTestEndpointWithTTL("db.example.com", endpoint.RecordTypeCNAME, 300, "lb.example.com"),
+			},
+		},
+		{
+			title:          "target annotation overrides the Traefik service",
+			traefikService: "traefik/traefik",
+			ingressRoutes: []*unstructured.Unstructured{
+				newTraefikRoute("IngressRoute", "annotated", map[string]string{
+					targetAnnotationKey:   "target.example.org",
+					hostnameAnnotationKey: "f.example.com",
+				},
+					"Host(`g.example.com`)",
+				),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("f.example.com", endpoint.RecordTypeCNAME, "target.example.org"),
+				newTestEndpoint("g.example.com", endpoint.RecordTypeCNAME, "target.example.org"),
+			},
+		},
+		{
+			title: "no targets without Traefik service or target annotation",
+			ingressRoutes: []*unstructured.Unstructured{
+				newTraefikRoute("IngressRoute", "untargeted", nil, "Host(`h.example.com`)"),
+			},
+			expected: []*endpoint.Endpoint{},
+		},
+		{
+			title:          "malformed routes are skipped",
+			traefikService: "traefik/traefik",
+			ingressRoutes: []*unstructured.Unstructured{
+				func() *unstructured.Unstructured {
+					route := newTraefikRoute("IngressRoute", "malformed", nil, "Host(`j.example.com`)")
+					spec := route.Object["spec"].(map[string]interface{})
+					spec["routes"] = append([]interface{}{"Host(`k.example.com`)"}, spec["routes"].([]interface{})...)
+					return route
+				}(),
+			},
+			expected: []*endpoint.Endpoint{
+				newTestEndpoint("j.example.com", endpoint.RecordTypeA, "1.2.3.4"),
+				newTestEndpoint("j.example.com", endpoint.RecordTypeCNAME, "lb.example.com"),
+			},
+		},
+		{
+			title:          "other controller is ignored",
+			traefikService: "traefik/traefik",
+			ingressRoutes: []*unstructured.Unstructured{
+				newTraefikRoute("IngressRoute", "other", map[string]string{
+					controllerAnnotationKey: "some-other-tool",
+				},
+					"Host(`i.example.com`)",
+				),
+			},
+			expected: []*endpoint.Endpoint{},
+		},
+	} {
+		tt := tt
+		t.Run(tt.title, func(t *testing.T) {
+			t.Parallel()
+
+			fakeKubernetesClient := fakeKube.NewSimpleClientset(traefikService)
+			fakeDynamicClient := fakeDynamic.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+				map[schema.GroupVersionResource]string{
+					traefikIngressRouteGVR:    "IngressRouteList",
+					traefikIngressRouteTCPGVR: "IngressRouteTCPList",
+				})
+
+			for _, route := range tt.ingressRoutes {
+				_, err := fakeDynamicClient.Resource(traefikIngressRouteGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), route, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+			for _, route := range tt.tcpRoutes {
+				_, err := fakeDynamicClient.Resource(traefikIngressRouteTCPGVR).Namespace(defaultTraefikNamespace).Create(context.Background(), route, metav1.CreateOptions{})
+				require.NoError(t, err)
+			}
+
+			source, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKubernetesClient, defaultTraefikNamespace, "", false, tt.traefikService, LoadBalancerTargetBoth)
+			require.NoError(t, err)
+
+			endpoints, err := source.Endpoints(context.Background())
+			require.NoError(t, err)
+			validateEndpoints(t, endpoints, tt.expected)
+		})
+	}
+}
+
+func TestNewTraefikSourceInvalidService(t *testing.T) {
+	t.Parallel()
+
+	fakeDynamicClient := fakeDynamic.NewSimpleDynamicClient(runtime.NewScheme())
+	_, err := NewTraefikSource(context.TODO(), fakeDynamicClient, fakeKube.NewSimpleClientset(), "", "", false, "traefik", LoadBalancerTargetBoth)
+	assert.Error(t, err)
+}